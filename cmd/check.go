@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/spf13/cobra"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+var checkCmd = &cobra.Command{
+	Use: "check",
+	Short: "Checks whether ARB files are in sync with POEditor, without modifying them. " +
+		"Must be run from the Flutter project root directory or its subdirectory.",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runCheck,
+	PreRunE:       versionGuard.GetFlutterConfigAndEnsureSufficientVersion,
+}
+
+var errOutOfSync = errors.New("ARB files are out of sync with POEditor")
+
+func init() {
+	checkCmd.Flags().StringP(projectIDFlag, "p", "", "POEditor project ID")
+	checkCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
+	checkCmd.Flags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
+	checkCmd.Flags().StringP(namingFlag, "", "",
+		"How term names are turned into message names: keep, dot-to-camel or snake-to-camel [default: keep]")
+	checkCmd.Flags().StringP(outputDirFlag, "o", "", `Output directory [default: "."]`)
+	checkCmd.Flags().StringSliceP(overrideLangsFlag, "", []string{}, "Override checked languages")
+	checkCmd.Flags().IntP(concurrencyFlag, "", 0, "Number of languages exported at the same time [default: 1]")
+	checkCmd.Flags().StringP(placeholderMismatchFlag, "", "",
+		"What to do with translations whose placeholders differ from the template: warn, skip or fail [default: warn]")
+	checkCmd.Flags().StringP(incompletePluralsFlag, "", "",
+		"What to do with plurals missing categories required by the language: warn, skip or fail [default: warn]")
+	checkCmd.Flags().Float64P(minCoverageFlag, "", 0, "Minimum percentage of translated messages in a language [default: 0]")
+	checkCmd.Flags().StringP(lowCoverageFlag, "", "",
+		"What to do with languages below the minimum coverage: skip or fail [default: fail]")
+	checkCmd.Flags().String(fromSnapshotFlag, "", "Convert exports saved with the snapshot command, instead of downloading them")
+	checkCmd.Flags().String(sourceDirFlag, "", "Convert POEditor JSON or ARB files from a directory, instead of downloading them")
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	log := getLogger(cmd)
+
+	logSub := log.Info("loading options").Sub()
+
+	sel, err := getOptionsSelector(cmd)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	options, err := sel.SelectOptions()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	poeCmd, err := NewPoeCommand(options, log)
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	logSub = log.Info("fetching project languages").Sub()
+	langs, err := poeCmd.GetExportLanguages()
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	outOfSync := false
+	check := func(checkCmd *poeCommand) error {
+		diffs, err := checkCmd.CheckLanguages(cmd.Context(), langs)
+		if err != nil {
+			return err
+		}
+
+		reportLog := checkCmd.log.Info("comparing ARB files in %s", checkCmd.options.OutputDir).Sub()
+		for _, diff := range diffs {
+			if diff.IsEmpty() {
+				reportLog.Success("%s: up to date", diff.FileName)
+				continue
+			}
+
+			outOfSync = true
+			diff.Report(reportLog)
+		}
+
+		return nil
+	}
+
+	if err := check(poeCmd); err != nil {
+		return err
+	}
+
+	for _, flavor := range options.Flavors {
+		flavorLog := log.Info("checking %s flavor", flavor.Name).Sub()
+		if err := check(poeCmd.flavorCommand(flavor, flavorLog)); err != nil {
+			return err
+		}
+	}
+
+	if outOfSync {
+		log.Error("out of sync, run poe2arb poe to update ARB files")
+		return errOutOfSync
+	}
+
+	log.Success("all ARB files are up to date")
+
+	return nil
+}
+
+// CheckLanguages converts given languages and compares them with the ARB files
+// in the output directory. ARB files present in the output directory, but not
+// exported, are reported too, except for the pseudo-locale. Languages below
+// the minimum coverage are not checked, as they wouldn't be exported either,
+// so their ARB files are reported as not exported.
+func (c *poeCommand) CheckLanguages(ctx context.Context, langs []poeditor.Language) ([]*arbDiff, error) {
+	var mu sync.Mutex
+	diffsByFile := map[string]*arbDiff{}
+	var fileNames []string
+
+	for _, lang := range langs {
+		if flutterLocale, err := flutter.ParseLocale(lang.Code); err == nil {
+			fileNames = append(fileNames, c.arbFileName(flutterLocale))
+		}
+	}
+
+	err := c.forEachLanguage(ctx, langs, func(ctx context.Context, log *log.Logger, lang poeditor.Language, flutterLocale flutter.Locale, template bool) error {
+		var b bytes.Buffer
+		coverage, err := c.ConvertLanguage(ctx, log, lang, flutterLocale, template, &b)
+		if err != nil {
+			return err
+		}
+
+		if ok, err := c.acceptCoverage(log, coverage, template); !ok {
+			return err
+		}
+
+		fileName := c.arbFileName(flutterLocale)
+
+		current, err := os.ReadFile(filepath.Join(c.options.OutputDir, fileName))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Error("reading %s failed: %s", fileName, err)
+			return err
+		}
+
+		diff, err := diffARB(fileName, b.Bytes(), current)
+		if err != nil {
+			log.Error("comparing %s failed: %s", fileName, err)
+			return err
+		}
+
+		mu.Lock()
+		diffsByFile[fileName] = diff
+		mu.Unlock()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []*arbDiff
+	for _, fileName := range fileNames {
+		if diff, ok := diffsByFile[fileName]; ok {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	// With overridden languages, other ARB files are intentionally not exported.
+	if len(c.options.OverrideLangs) == 0 {
+		files, err := os.ReadDir(c.options.OutputDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			c.log.Error("reading %s failed: %s", c.options.OutputDir, err)
+			return nil, err
+		}
+
+		for _, file := range files {
+			name := file.Name()
+			if file.IsDir() || !strings.HasPrefix(name, c.options.ARBPrefix) || filepath.Ext(name) != ".arb" {
+				continue
+			}
+
+			if _, ok := diffsByFile[name]; !ok && !c.isPseudoFile(name) {
+				diffs = append(diffs, &arbDiff{FileName: name, NotExported: true})
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+func (c *poeCommand) arbFileName(flutterLocale flutter.Locale) string {
+	return fmt.Sprintf("%s%s.arb", c.options.ARBPrefix, flutterLocale.StringFilename())
+}
+
+// arbDiff describes differences between an ARB file generated from POEditor
+// and the one present on the disk.
+type arbDiff struct {
+	FileName string
+
+	// Missing is true when the file is not present on the disk.
+	Missing bool
+	// NotExported is true when the file is present on the disk,
+	// but its language was not exported from POEditor.
+	NotExported bool
+	// FormattingChanged is true when messages are equal, but the file contents differ.
+	FormattingChanged bool
+
+	// Added are messages present in POEditor, but not in the file.
+	Added []string
+	// Removed are messages present in the file, but not in POEditor.
+	Removed []string
+	// Changed are messages whose translation or attributes differ.
+	Changed []string
+}
+
+func (d *arbDiff) IsEmpty() bool {
+	return !d.Missing && !d.NotExported && !d.FormattingChanged &&
+		len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d *arbDiff) Report(log *log.Logger) {
+	switch {
+	case d.Missing:
+		log.Error("%s: missing file, %d messages in POEditor", d.FileName, len(d.Added))
+		return
+	case d.NotExported:
+		log.Error("%s: language is not exported from POEditor", d.FileName)
+		return
+	}
+
+	logSub := log.Error("%s: %d added, %d removed, %d changed",
+		d.FileName, len(d.Added), len(d.Removed), len(d.Changed)).Sub()
+
+	if len(d.Added) > 0 {
+		logSub.Info("added: %s", strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		logSub.Info("removed: %s", strings.Join(d.Removed, ", "))
+	}
+	if len(d.Changed) > 0 {
+		logSub.Info("changed: %s", strings.Join(d.Changed, ", "))
+	}
+	if d.FormattingChanged {
+		logSub.Info("file formatting differs")
+	}
+}
+
+// diffARB compares the generated ARB contents with the current one.
+// Current being nil means the file does not exist.
+func diffARB(fileName string, generated, current []byte) (*arbDiff, error) {
+	diff := &arbDiff{FileName: fileName}
+
+	generatedARB := orderedmap.New[string, any]()
+	if err := json.Unmarshal(generated, &generatedARB); err != nil {
+		return nil, fmt.Errorf("decoding generated ARB: %w", err)
+	}
+
+	if current == nil {
+		diff.Missing = true
+		diff.Added = arbMessageNames(generatedARB)
+		return diff, nil
+	}
+
+	currentARB := orderedmap.New[string, any]()
+	if err := json.Unmarshal(current, &currentARB); err != nil {
+		return nil, fmt.Errorf("decoding current ARB: %w", err)
+	}
+
+	for _, name := range arbMessageNames(generatedARB) {
+		if _, ok := currentARB.Get(name); !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+
+		if !arbValuesEqual(generatedARB, currentARB, name) || !arbValuesEqual(generatedARB, currentARB, "@"+name) {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+
+	for _, name := range arbMessageNames(currentARB) {
+		if _, ok := generatedARB.Get(name); !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	if len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		diff.FormattingChanged = !bytes.Equal(generated, current)
+	}
+
+	return diff, nil
+}
+
+func arbMessageNames(arb *orderedmap.OrderedMap[string, any]) []string {
+	var names []string
+	for pair := arb.Oldest(); pair != nil; pair = pair.Next() {
+		if !strings.HasPrefix(pair.Key, "@") {
+			names = append(names, pair.Key)
+		}
+	}
+	return names
+}
+
+func arbValuesEqual(a, b *orderedmap.OrderedMap[string, any], key string) bool {
+	aValue, _ := a.Get(key)
+	bValue, _ := b.Get(key)
+
+	return reflect.DeepEqual(aValue, bValue)
+}
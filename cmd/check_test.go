@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffARB(t *testing.T) {
+	generated := []byte(`{
+    "@@locale": "en",
+    "added": "Added",
+    "changed": "Changed",
+    "changedAttributes": "Hello {name}",
+    "@changedAttributes": {
+        "placeholders": {
+            "name": {
+                "type": "String"
+            }
+        }
+    },
+    "same": "Same"
+}
+`)
+
+	t.Run("missing file", func(t *testing.T) {
+		diff, err := diffARB("app_en.arb", generated, nil)
+
+		assert.NoError(t, err)
+		assert.False(t, diff.IsEmpty())
+		assert.True(t, diff.Missing)
+		assert.Equal(t, []string{"added", "changed", "changedAttributes", "same"}, diff.Added)
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		diff, err := diffARB("app_en.arb", generated, generated)
+
+		assert.NoError(t, err)
+		assert.True(t, diff.IsEmpty())
+	})
+
+	t.Run("different formatting", func(t *testing.T) {
+		current := []byte(`{"@@locale": "en", "added": "Added", "changed": "Changed",
+			"changedAttributes": "Hello {name}",
+			"@changedAttributes": {"placeholders": {"name": {"type": "String"}}},
+			"same": "Same"}`)
+
+		diff, err := diffARB("app_en.arb", generated, current)
+
+		assert.NoError(t, err)
+		assert.False(t, diff.IsEmpty())
+		assert.True(t, diff.FormattingChanged)
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Removed)
+		assert.Empty(t, diff.Changed)
+	})
+
+	t.Run("messages differ", func(t *testing.T) {
+		current := []byte(`{
+    "@@locale": "en",
+    "changed": "Changed locally",
+    "changedAttributes": "Hello {name}",
+    "@changedAttributes": {
+        "placeholders": {
+            "name": {
+                "type": "Object"
+            }
+        }
+    },
+    "removed": "Removed",
+    "same": "Same"
+}
+`)
+
+		diff, err := diffARB("app_en.arb", generated, current)
+
+		assert.NoError(t, err)
+		assert.False(t, diff.IsEmpty())
+		assert.False(t, diff.FormattingChanged)
+		assert.Equal(t, []string{"added"}, diff.Added)
+		assert.Equal(t, []string{"removed"}, diff.Removed)
+		assert.Equal(t, []string{"changed", "changedAttributes"}, diff.Changed)
+	})
+
+	t.Run("invalid current file", func(t *testing.T) {
+		_, err := diffARB("app_en.arb", generated, []byte("{"))
+
+		assert.Error(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/leancodepl/poe2arb/convert/poe2arb"
 	"github.com/leancodepl/poe2arb/flutter"
@@ -23,8 +24,10 @@ var (
 )
 
 const (
-	langFlag       = "lang"
-	noTemplateFlag = "no-template"
+	langFlag         = "lang"
+	noTemplateFlag   = "no-template"
+	useEscapingFlag  = "use-escaping"
+	strictSyntaxFlag = "strict-syntax"
 )
 
 func init() {
@@ -32,7 +35,12 @@ func init() {
 	convertCmd.MarkPersistentFlagRequired(langFlag)
 
 	convertCmd.PersistentFlags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
+	convertCmd.PersistentFlags().String(namingFlag, string(poe2arb.NamingKeep),
+		"How term names are turned into message names: keep, dot-to-camel or snake-to-camel")
 	convertCmd.PersistentFlags().Bool(noTemplateFlag, false, "Whether the output should NOT be generated as a template ARB")
+	convertCmd.PersistentFlags().Bool(useEscapingFlag, false, "Whether apostrophes quote literal text, as with gen-l10n use-escaping option")
+	convertCmd.PersistentFlags().Bool(strictSyntaxFlag, false,
+		"Whether brackets not starting a placeholder and invalid translations are errors")
 
 	convertCmd.AddCommand(convertIoCmd)
 }
@@ -41,6 +49,13 @@ func runConvertIo(cmd *cobra.Command, args []string) error {
 	lang, _ := cmd.Flags().GetString(langFlag)
 	noTemplate, _ := cmd.Flags().GetBool(noTemplateFlag)
 	termPrefix, _ := cmd.Flags().GetString(termPrefixFlag)
+	useEscaping, _ := cmd.Flags().GetBool(useEscapingFlag)
+	strictSyntax, _ := cmd.Flags().GetBool(strictSyntaxFlag)
+	naming, _ := cmd.Flags().GetString(namingFlag)
+
+	if !slices.Contains(poe2arb.NamingStrategies, poe2arb.NamingStrategy(naming)) {
+		return fmt.Errorf("invalid naming strategy %q, must be one of: keep, dot-to-camel, snake-to-camel", naming)
+	}
 
 	flutterLocale, err := flutter.ParseLocale(lang)
 	if err != nil {
@@ -52,6 +67,9 @@ func runConvertIo(cmd *cobra.Command, args []string) error {
 		Template:                  !noTemplate,
 		RequireResourceAttributes: true,
 		TermPrefix:                termPrefix,
+		Naming:                    poe2arb.NamingStrategy(naming),
+		UseEscaping:               useEscaping,
+		StrictSyntax:              strictSyntax,
 	})
 
 	return conv.Convert(os.Stdout)
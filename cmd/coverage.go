@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+)
+
+// lowCoverageMode decides what happens to languages translated below the minimum coverage.
+type lowCoverageMode string
+
+const (
+	// lowCoverageFail fails the export.
+	lowCoverageFail lowCoverageMode = "fail"
+	// lowCoverageSkip leaves the language out, removing its existing ARB file.
+	lowCoverageSkip lowCoverageMode = "skip"
+)
+
+var lowCoverageModes = []lowCoverageMode{lowCoverageFail, lowCoverageSkip}
+
+// reportCoverage logs how much of the language is translated, along with the missing messages.
+func reportCoverage(log *log.Logger, lang poeditor.Language, coverage *poe2arb.Coverage) {
+	msg := fmt.Sprintf("%.1f%% translated, %d of %d messages",
+		coverage.Percentage(), coverage.Total-len(coverage.Missing), coverage.Total)
+	if !lang.Updated.IsZero() {
+		msg += ", last updated " + lang.Updated.Format("2006-01-02 15:04")
+	}
+
+	if len(coverage.Missing) == 0 {
+		log.Info(msg)
+		return
+	}
+
+	log.Warning(msg).Sub().Info("missing: %s", strings.Join(coverage.Missing, ", "))
+}
+
+// acceptCoverage reports whether the language is translated enough to be exported.
+// Template language is always exported, as other languages fall back to it.
+func (c *poeCommand) acceptCoverage(log *log.Logger, coverage *poe2arb.Coverage, template bool) (bool, error) {
+	minCoverage := c.options.MinCoverage
+	if template || coverage.Percentage() >= minCoverage {
+		return true, nil
+	}
+
+	msg := fmt.Sprintf("%.1f%% translated, below the minimum coverage of %g%%", coverage.Percentage(), minCoverage)
+
+	if c.options.LowCoverage == lowCoverageSkip {
+		log.Warning(msg + ", skipping")
+		return false, nil
+	}
+
+	log.Error(msg)
+	return false, errors.New(msg)
+}
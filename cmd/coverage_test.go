@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptCoverage(t *testing.T) {
+	halfTranslated := &poe2arb.Coverage{Total: 4, Missing: []string{"a", "b"}}
+
+	type testCase struct {
+		Name        string
+		MinCoverage float64
+		LowCoverage lowCoverageMode
+		Template    bool
+		Expected    bool
+		ExpectedErr string
+	}
+
+	testCases := []testCase{
+		{"no minimum", 0, lowCoverageFail, false, true, ""},
+		{"above minimum", 50, lowCoverageFail, false, true, ""},
+		{"below minimum, fail", 75, lowCoverageFail, false, false, "50.0% translated, below the minimum coverage of 75%"},
+		{"below minimum, skip", 75, lowCoverageSkip, false, false, ""},
+		{"template below minimum", 75, lowCoverageFail, true, true, ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			c := &poeCommand{options: &poeOptions{
+				MinCoverage: testCase.MinCoverage,
+				LowCoverage: testCase.LowCoverage,
+			}}
+
+			ok, err := c.acceptCoverage(log.New(new(bytes.Buffer)), halfTranslated, testCase.Template)
+
+			assert.Equal(t, testCase.Expected, ok)
+			if testCase.ExpectedErr != "" {
+				assert.EqualError(t, err, testCase.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExportLowCoverageSkip(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}, {Name: "Polish", Code: "pl"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "title", "definition": "Welcome", "term_plural": ""},
+			{"term": "subtitle", "definition": "Hello", "term_plural": ""}
+		]`,
+		"pl.json": `[
+			{"term": "title", "definition": "Witaj", "term_plural": ""},
+			{"term": "subtitle", "definition": "", "term_plural": ""}
+		]`,
+	})
+
+	root := t.TempDir()
+	outputDir := filepath.Join(root, "lib", "l10n")
+	writeTestFiles(t, root, map[string]string{
+		"lib/l10n/app_pl.arb":                         "{\n    \"@@locale\": \"pl\",\n    \"title\": \"Stare\"\n}\n",
+		"android/app/src/main/res/values/strings.xml": "<resources/>",
+	})
+
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           outputDir,
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		MinCoverage:         75,
+		LowCoverage:         lowCoverageSkip,
+		FromSnapshot:        snapshotDir,
+		PlatformLocales: &platformLocalesOptions{
+			IOSDir:        filepath.Join(root, "ios", "Runner"),
+			AndroidResDir: filepath.Join(root, "android", "app", "src", "main", "res"),
+		},
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.NoError(t, err)
+
+	// Outdated translations of the skipped language are not shipped.
+	assert.FileExists(t, filepath.Join(outputDir, "app_en.arb"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "app_pl.arb"))
+
+	localesConfig, err := os.ReadFile(filepath.Join(root, "android", "app", "src", "main", "res", "xml", "locales_config.xml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(localesConfig), `<locale android:name="en"/>`)
+	assert.NotContains(t, string(localesConfig), `"pl"`)
+
+	// check expects the skipped language's ARB file to be removed too.
+	writeTestFiles(t, root, map[string]string{"lib/l10n/app_pl.arb": "{\n    \"@@locale\": \"pl\"\n}\n"})
+	langs, err := c.GetExportLanguages()
+	assert.NoError(t, err)
+
+	diffs, err := c.CheckLanguages(context.Background(), langs)
+	assert.NoError(t, err)
+	assert.Contains(t, diffs, &arbDiff{FileName: "app_pl.arb", NotExported: true})
+}
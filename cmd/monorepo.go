@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/spf13/pflag"
+)
+
+const monorepoFlag = "monorepo"
+
+// monorepoPackage is a Flutter package exported in the monorepo mode.
+type monorepoPackage struct {
+	// Name is the package directory relative to the monorepo root.
+	Name    string
+	Options *poeOptions
+}
+
+// projectExports shares languages and JSON exports of a POEditor project between
+// the packages and flavors exported from it, so that each of them is downloaded only once.
+// Failed downloads are not cached, so they may be retried.
+type projectExports struct {
+	langsMu sync.Mutex
+	langs   []poeditor.Language
+
+	mu      sync.Mutex
+	exports map[string]*projectExport
+}
+
+type projectExport struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func newProjectExports() *projectExports {
+	return &projectExports{exports: map[string]*projectExport{}}
+}
+
+// Languages returns the project languages, calling fetch only if they weren't fetched yet.
+func (p *projectExports) Languages(fetch func() ([]poeditor.Language, error)) ([]poeditor.Language, error) {
+	p.langsMu.Lock()
+	defer p.langsMu.Unlock()
+
+	if p.langs == nil {
+		langs, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		p.langs = langs
+	}
+
+	return p.langs, nil
+}
+
+// Export returns the JSON export of a language, calling fetch only if it wasn't downloaded yet.
+func (p *projectExports) Export(langCode string, fetch func() (io.ReadCloser, error)) (io.ReadCloser, error) {
+	p.mu.Lock()
+	export, ok := p.exports[langCode]
+	if !ok {
+		export = &projectExport{}
+		p.exports[langCode] = export
+	}
+	p.mu.Unlock()
+
+	export.mu.Lock()
+	defer export.mu.Unlock()
+
+	if export.data == nil {
+		body, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("reading export: %w", err)
+		}
+		export.data = data
+	}
+
+	return io.NopCloser(bytes.NewReader(export.data)), nil
+}
+
+// findMonorepoPackages finds Flutter packages with l10n.yaml inside root and selects their options.
+// Packages without a POEditor project ID are skipped.
+func findMonorepoPackages(log *log.Logger, root string, flags *pflag.FlagSet, env *envVars) ([]*monorepoPackage, error) {
+	for _, flag := range []string{outputDirFlag, termPrefixFlag} {
+		if value, _ := flags.GetString(flag); value != "" {
+			return nil, fmt.Errorf("--%s can't be used with --%s, set it in l10n.yaml of each package instead", flag, monorepoFlag)
+		}
+	}
+
+	cfgs, err := flutter.FindPackages(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []*monorepoPackage
+	for _, cfg := range cfgs {
+		name, err := filepath.Rel(root, cfg.RootDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := versionGuard.ensureSufficientVersion(cfg.L10n.Poe2ArbVersion); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		sel := &poeOptionsSelector{
+			flags:   flags,
+			l10n:    cfg.L10n,
+			env:     env,
+			rootDir: cfg.RootDir,
+		}
+		options, err := sel.SelectOptions()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if options.ProjectID == "" {
+			log.Info("%s: no POEditor project ID, skipping", name)
+			continue
+		}
+
+		// arb-dir is relative to the package, not to the current directory.
+		if !filepath.IsAbs(options.OutputDir) {
+			options.OutputDir = filepath.Join(cfg.RootDir, options.OutputDir)
+		}
+		for _, flavor := range options.Flavors {
+			if !filepath.IsAbs(flavor.OutputDir) {
+				flavor.OutputDir = filepath.Join(cfg.RootDir, flavor.OutputDir)
+			}
+		}
+
+		pkgs = append(pkgs, &monorepoPackage{Name: name, Options: options})
+	}
+
+	return pkgs, nil
+}
+
+// ExportMonorepo exports every package, grouped by their POEditor project. Project languages
+// and their exports are downloaded once per project and converted for each package,
+// filtered by its term prefix. A failed package doesn't stop the other ones.
+func ExportMonorepo(ctx context.Context, log *log.Logger, pkgs []*monorepoPackage) error {
+	type projectKey struct{ projectID, token string }
+
+	var keys []projectKey
+	groups := map[projectKey][]*monorepoPackage{}
+	for _, pkg := range pkgs {
+		key := projectKey{pkg.Options.ProjectID, pkg.Options.Token}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], pkg)
+	}
+
+	var failed []string
+	for _, key := range keys {
+		projectLog := log.Info("POEditor project %s", key.projectID).Sub()
+		exports := newProjectExports()
+
+		for _, pkg := range groups[key] {
+			pkgLog := projectLog.Info("package %s", pkg.Name).Sub()
+
+			if err := exportMonorepoPackage(ctx, pkgLog, pkg, exports); err != nil {
+				failed = append(failed, pkg.Name)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		logSub := log.Error("failed exporting %d of %d packages", len(failed), len(pkgs)).Sub()
+		for _, name := range failed {
+			logSub.Error(name)
+		}
+
+		return errors.New("monorepo export failed")
+	}
+
+	return nil
+}
+
+func exportMonorepoPackage(ctx context.Context, log *log.Logger, pkg *monorepoPackage, exports *projectExports) error {
+	poeCmd, err := NewPoeCommand(pkg.Options, log)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	poeCmd.exports = exports
+
+	return poeCmd.Export(ctx)
+}
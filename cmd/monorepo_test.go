@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o777))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o666))
+	}
+}
+
+func newTestMonorepoFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String(projectIDFlag, "", "")
+	flags.String(tokenFlag, "", "")
+	flags.String(termPrefixFlag, "", "")
+	flags.String(outputDirFlag, "", "")
+	flags.String(fromSnapshotFlag, "", "")
+	assert.NoError(t, flags.Parse(args))
+
+	return flags
+}
+
+func TestProjectExports(t *testing.T) {
+	exports := newProjectExports()
+
+	langsFetched := 0
+	fetchLangs := func() ([]poeditor.Language, error) {
+		langsFetched++
+		return []poeditor.Language{{Code: "en"}}, nil
+	}
+
+	for range 2 {
+		langs, err := exports.Languages(fetchLangs)
+		assert.NoError(t, err)
+		assert.Equal(t, []poeditor.Language{{Code: "en"}}, langs)
+	}
+	assert.Equal(t, 1, langsFetched)
+
+	_, err := exports.Export("en", func() (io.ReadCloser, error) {
+		return nil, errors.New("connection reset")
+	})
+	assert.EqualError(t, err, "connection reset")
+
+	exportsFetched := 0
+	fetchExport := func() (io.ReadCloser, error) {
+		exportsFetched++
+		return io.NopCloser(bytes.NewBufferString("[]")), nil
+	}
+
+	for range 2 {
+		export, err := exports.Export("en", fetchExport)
+		assert.NoError(t, err)
+
+		data, err := io.ReadAll(export)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]", string(data))
+	}
+	assert.Equal(t, 1, exportsFetched, "failed download should be retried once, then shared")
+}
+
+func TestFindMonorepoPackages(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"packages/loans/pubspec.yaml": "",
+		"packages/loans/l10n.yaml":    "{poeditor-project-id: 123, poeditor-term-prefix: loans}",
+		"packages/cards/pubspec.yaml": "",
+		"packages/cards/l10n.yaml":    "{poeditor-project-id: 123, poeditor-term-prefix: cards, arb-dir: lib/src/l10n}",
+		"packages/other/pubspec.yaml": "",
+		"packages/other/l10n.yaml":    "{arb-dir: lib/l10n}",
+	})
+
+	t.Run("finds packages with project ID", func(t *testing.T) {
+		pkgs, err := findMonorepoPackages(log.New(new(bytes.Buffer)), root, newTestMonorepoFlags(t), &envVars{Token: "token"})
+
+		assert.NoError(t, err)
+		if assert.Len(t, pkgs, 2) {
+			assert.Equal(t, filepath.Join("packages", "cards"), pkgs[0].Name)
+			assert.Equal(t, "cards", pkgs[0].Options.TermPrefix)
+			assert.Equal(t, filepath.Join(root, "packages", "cards", "lib", "src", "l10n"), pkgs[0].Options.OutputDir)
+			assert.Equal(t, filepath.Join(root, "packages", "cards", ".dart_tool", "poe2arb", "state.json"), pkgs[0].Options.StatePath)
+
+			assert.Equal(t, filepath.Join("packages", "loans"), pkgs[1].Name)
+			assert.Equal(t, "loans", pkgs[1].Options.TermPrefix)
+			assert.Equal(t, filepath.Join(root, "packages", "loans", "lib", "l10n"), pkgs[1].Options.OutputDir)
+			assert.Equal(t, "token", pkgs[1].Options.Token)
+		}
+	})
+
+	t.Run("output directory flag", func(t *testing.T) {
+		flags := newTestMonorepoFlags(t, "--"+outputDirFlag, "lib/l10n")
+
+		_, err := findMonorepoPackages(log.New(new(bytes.Buffer)), root, flags, &envVars{})
+
+		assert.EqualError(t, err, "--output-dir can't be used with --monorepo, set it in l10n.yaml of each package instead")
+	})
+}
+
+func TestExportMonorepo(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "loans:title", "definition": "Loans", "term_plural": ""},
+			{"term": "cards:title", "definition": "Cards", "term_plural": ""},
+			{"term": "title", "definition": "App", "term_plural": ""}
+		]`,
+	})
+
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"packages/loans/pubspec.yaml": "",
+		"packages/loans/l10n.yaml":    "{poeditor-project-id: 123, poeditor-term-prefix: loans}",
+		"packages/cards/pubspec.yaml": "",
+		"packages/cards/l10n.yaml":    "{poeditor-project-id: 123, poeditor-term-prefix: cards, template-arb-file: cards_en.arb}",
+	})
+
+	flags := newTestMonorepoFlags(t, "--"+fromSnapshotFlag, snapshotDir)
+	logger := log.New(new(bytes.Buffer))
+	pkgs, err := findMonorepoPackages(logger, root, flags, &envVars{})
+	assert.NoError(t, err)
+
+	err = ExportMonorepo(context.Background(), logger, pkgs)
+	assert.NoError(t, err)
+
+	loans, err := os.ReadFile(filepath.Join(root, "packages", "loans", "lib", "l10n", "app_en.arb"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n    \"@@locale\": \"en\",\n    \"title\": \"Loans\"\n}\n", string(loans))
+
+	cards, err := os.ReadFile(filepath.Join(root, "packages", "cards", "lib", "l10n", "cards_en.arb"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n    \"@@locale\": \"en\",\n    \"title\": \"Cards\"\n}\n", string(cards))
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/leancodepl/poe2arb/convert/poe2native"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+)
+
+// nativeStringsOptions describes native strings files generated for a platform.
+type nativeStringsOptions struct {
+	Platform   poe2native.Platform
+	TermPrefix string
+	// Dir is the iOS Runner directory or the Android res directory.
+	Dir string
+}
+
+// filePath returns the path of the native strings file of the given locale.
+// The template language is Android's default, used when no other language matches.
+func (o *nativeStringsOptions) filePath(flutterLocale flutter.Locale, template bool) string {
+	if o.Platform == poe2native.PlatformIOS {
+		return filepath.Join(o.Dir, flutterLocale.IOSDirName(), "InfoPlist.strings")
+	}
+
+	dir := flutterLocale.AndroidDirName()
+	if template {
+		dir = "values"
+	}
+
+	return filepath.Join(o.Dir, dir, "strings.xml")
+}
+
+// ConvertNativeStrings converts native strings of a single language for every platform.
+// The files are kept to be written with WriteNativeStrings, once the ARB files are saved.
+func (c *poeCommand) ConvertNativeStrings(
+	ctx context.Context,
+	log *log.Logger,
+	lang poeditor.Language,
+	flutterLocale flutter.Locale,
+	template bool,
+) error {
+	for _, native := range c.options.NativeStrings {
+		logSub := log.Info("converting JSON to %s strings", native.Platform).Sub()
+
+		path := native.filePath(flutterLocale, template)
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logSub.Error("reading %s failed: %s", path, err)
+			return err
+		}
+
+		export, err := c.FetchExport(ctx, logSub, lang.Code)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		conv := poe2native.NewConverter(export, &poe2native.ConverterOptions{
+			Platform:   native.Platform,
+			TermPrefix: native.TermPrefix,
+			Existing:   existing,
+		})
+		err = conv.Convert(&b)
+		export.Close()
+		if err != nil {
+			logSub.Error(err.Error())
+			return fmt.Errorf("converting %s strings %s: %w", native.Platform, path, err)
+		}
+
+		c.nativeFilesMu.Lock()
+		if c.nativeFiles == nil {
+			c.nativeFiles = map[string][]byte{}
+		}
+		c.nativeFiles[path] = b.Bytes()
+		c.nativeFilesMu.Unlock()
+	}
+
+	return nil
+}
+
+// WriteNativeStrings writes the converted native strings files. Files with unchanged
+// contents are left untouched.
+func (c *poeCommand) WriteNativeStrings() error {
+	if len(c.nativeFiles) == 0 {
+		return nil
+	}
+
+	logSub := c.log.Info("saving native strings").Sub()
+
+	var paths []string
+	for path := range c.nativeFiles {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		contents := c.nativeFiles[path]
+
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, contents) {
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+
+		if err := os.WriteFile(path, contents, 0o666); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+
+		logSub.Info("saved %s", path)
+	}
+
+	c.nativeFiles = nil
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/convert/poe2native"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportNativeStrings(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}, {Name: "Portuguese (BR)", Code: "pt-br"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "title", "definition": "Welcome", "term_plural": ""},
+			{"term": "ios:CFBundleDisplayName", "definition": "Bank", "term_plural": ""},
+			{"term": "android:app_name", "definition": "Bank", "term_plural": ""}
+		]`,
+		"pt-br.json": `[
+			{"term": "title", "definition": "Bem-vindo", "term_plural": ""},
+			{"term": "ios:CFBundleDisplayName", "definition": "Banco", "term_plural": ""},
+			{"term": "android:app_name", "definition": "Banco", "term_plural": ""}
+		]`,
+	})
+
+	root := t.TempDir()
+	// Strings maintained by hand are kept.
+	writeTestFiles(t, root, map[string]string{
+		"android/app/src/main/res/values/strings.xml": `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="channel_id" translatable="false">payments</string>
+    <string name="app_name">Old name</string>
+</resources>
+`,
+	})
+
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           filepath.Join(root, "lib", "l10n"),
+		Concurrency:         2,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		FromSnapshot:        snapshotDir,
+		NativeStrings: []*nativeStringsOptions{
+			{Platform: poe2native.PlatformIOS, TermPrefix: "ios", Dir: filepath.Join(root, "ios", "Runner")},
+			{Platform: poe2native.PlatformAndroid, TermPrefix: "android", Dir: filepath.Join(root, "android", "app", "src", "main", "res")},
+		},
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		"lib/l10n/app_en.arb":                      "{\n    \"@@locale\": \"en\",\n    \"title\": \"Welcome\"\n}\n",
+		"ios/Runner/en.lproj/InfoPlist.strings":    "\"CFBundleDisplayName\" = \"Bank\";\n",
+		"ios/Runner/pt-BR.lproj/InfoPlist.strings": "\"CFBundleDisplayName\" = \"Banco\";\n",
+		"android/app/src/main/res/values/strings.xml": `<resources>
+    <string name="channel_id" translatable="false">payments</string>
+    <string name="app_name">Bank</string>
+</resources>`,
+		"android/app/src/main/res/values-pt-rBR/strings.xml": `<string name="app_name">Banco</string>`,
+	}
+	for name, contents := range expected {
+		actual, err := os.ReadFile(filepath.Join(root, name))
+		assert.NoError(t, err)
+		assert.Contains(t, string(actual), contents, name)
+	}
+
+	assert.NoFileExists(t, filepath.Join(root, "android", "app", "src", "main", "res", "values-en", "strings.xml"))
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+)
+
+// platformLocalesOptions describes platform files declaring the supported locales.
+type platformLocalesOptions struct {
+	// IOSDir is the iOS Runner directory, containing Info.plist.
+	IOSDir string
+	// AndroidResDir is the Android res directory, containing xml/locales_config.xml.
+	AndroidResDir string
+}
+
+var (
+	plistLocalizationsRegexp = regexp.MustCompile(`(?m)^([ \t]*)<key>CFBundleLocalizations</key>\s*(?:<array>(?s:(.*?))</array>|<array\s*/>)`)
+	plistStringRegexp        = regexp.MustCompile(`<string>([^<]*)</string>`)
+	plistDictEndRegexp       = regexp.MustCompile(`(?m)^[ \t]*</dict>\s*</plist>`)
+
+	androidLocaleRegexp    = regexp.MustCompile(`(?m)^([ \t]*)<locale\s+android:name="([^"]*)"\s*/>[ \t]*\n?`)
+	androidConfigEndRegexp = regexp.MustCompile(`(?m)^[ \t]*</locale-config>`)
+)
+
+const androidLocalesConfig = `<?xml version="1.0" encoding="utf-8"?>
+<locale-config xmlns:android="http://schemas.android.com/apk/res/android">
+</locale-config>
+`
+
+// SyncPlatformLocales declares the locales of the ARB files in the output directory
+// in iOS Info.plist and Android locales_config.xml, preserving the rest of the files.
+// Platforms missing in the project are skipped.
+func (c *poeCommand) SyncPlatformLocales() error {
+	platforms := c.options.PlatformLocales
+	if platforms == nil {
+		return nil
+	}
+
+	logSub := c.log.Info("updating platform locales").Sub()
+
+	tags, err := c.exportedLanguageTags()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	if _, err := os.Stat(platforms.IOSDir); err == nil {
+		path := filepath.Join(platforms.IOSDir, "Info.plist")
+		if err := updatePlatformFile(logSub, path, nil, tags, updateInfoPlistLocales); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(platforms.AndroidResDir); err == nil {
+		path := filepath.Join(platforms.AndroidResDir, "xml", "locales_config.xml")
+		_, err := os.Stat(path)
+		created := errors.Is(err, os.ErrNotExist)
+
+		if err := updatePlatformFile(logSub, path, []byte(androidLocalesConfig), tags, updateAndroidLocales); err != nil {
+			return err
+		}
+
+		if created {
+			logSub.Info(`reference it in AndroidManifest.xml application with android:localeConfig="@xml/locales_config"`)
+		}
+	}
+
+	return nil
+}
+
+// exportedLanguageTags returns sorted language tags of the ARB files in the output directory.
+// The pseudo-locale is left out, as it's not meant to be shipped.
+func (c *poeCommand) exportedLanguageTags() ([]string, error) {
+	files, err := os.ReadDir(c.options.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, c.options.ARBPrefix) || filepath.Ext(name) != ".arb" || c.isPseudoFile(name) {
+			continue
+		}
+
+		locale, err := flutter.ParseLocale(strings.TrimSuffix(strings.TrimPrefix(name, c.options.ARBPrefix), ".arb"))
+		if err != nil {
+			continue
+		}
+		tags = append(tags, locale.LanguageTag())
+	}
+
+	slices.Sort(tags)
+
+	return tags, nil
+}
+
+type localesUpdater func(contents []byte, tags []string) (updated []byte, declared []string, err error)
+
+// updatePlatformFile updates the locales declared in the file and reports the changes.
+// A missing file is created from the template, or skipped if there's none.
+func updatePlatformFile(log *log.Logger, path string, template []byte, tags []string, update localesUpdater) error {
+	name := filepath.Base(path)
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && template != nil {
+		contents = template
+	} else if errors.Is(err, os.ErrNotExist) {
+		log.Warning("%s not found, skipping", path)
+		return nil
+	} else if err != nil {
+		log.Error("reading %s failed: %s", name, err)
+		return err
+	}
+
+	updated, declared, err := update(contents, tags)
+	if err != nil {
+		log.Error("updating %s failed: %s", name, err)
+		return fmt.Errorf("updating %s: %w", name, err)
+	}
+
+	var added, removed []string
+	for _, tag := range tags {
+		if !slices.Contains(declared, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range declared {
+		if !slices.Contains(tags, tag) {
+			removed = append(removed, tag)
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil && bytes.Equal(updated, contents) {
+		log.Info("%s: up to date", name)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		log.Error("writing %s failed: %s", name, err)
+		return err
+	}
+	if err := os.WriteFile(path, updated, 0o666); err != nil {
+		log.Error("writing %s failed: %s", name, err)
+		return err
+	}
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "reordered")
+	}
+	log.Success("%s: %s", name, strings.Join(changes, ", "))
+
+	return nil
+}
+
+// updateInfoPlistLocales replaces the CFBundleLocalizations array, adding it if missing.
+func updateInfoPlistLocales(contents []byte, tags []string) ([]byte, []string, error) {
+	s := string(contents)
+
+	if match := plistLocalizationsRegexp.FindStringSubmatchIndex(s); match != nil {
+		indent := s[match[2]:match[3]]
+
+		var declared []string
+		if match[4] != -1 {
+			for _, m := range plistStringRegexp.FindAllStringSubmatch(s[match[4]:match[5]], -1) {
+				declared = append(declared, m[1])
+			}
+		}
+
+		updated := s[:match[0]] + plistLocalizations(indent, tags) + s[match[1]:]
+		return []byte(updated), declared, nil
+	}
+
+	match := plistDictEndRegexp.FindStringIndex(s)
+	if match == nil {
+		return nil, nil, errors.New("top-level dict not found")
+	}
+
+	updated := s[:match[0]] + plistLocalizations("\t", tags) + "\n" + s[match[0]:]
+	return []byte(updated), nil, nil
+}
+
+func plistLocalizations(indent string, tags []string) string {
+	var sb strings.Builder
+	sb.WriteString(indent + "<key>CFBundleLocalizations</key>\n")
+	sb.WriteString(indent + "<array>\n")
+	for _, tag := range tags {
+		sb.WriteString(indent + indentUnit(indent) + "<string>" + tag + "</string>\n")
+	}
+	sb.WriteString(indent + "</array>")
+	return sb.String()
+}
+
+// updateAndroidLocales replaces the locale elements of locales_config.xml.
+func updateAndroidLocales(contents []byte, tags []string) ([]byte, []string, error) {
+	s := string(contents)
+
+	matches := androidLocaleRegexp.FindAllStringSubmatchIndex(s, -1)
+
+	var declared []string
+	var insertAt int
+	indent := "    "
+	if len(matches) > 0 {
+		insertAt = matches[0][0]
+		indent = s[matches[0][2]:matches[0][3]]
+
+		// Remove the existing locales, from the last one to keep the indices valid.
+		for i := len(matches) - 1; i >= 0; i-- {
+			match := matches[i]
+			declared = append([]string{s[match[4]:match[5]]}, declared...)
+			s = s[:match[0]] + s[match[1]:]
+		}
+	} else {
+		match := androidConfigEndRegexp.FindStringIndex(s)
+		if match == nil {
+			return nil, nil, errors.New("locale-config element not found")
+		}
+		insertAt = match[0]
+	}
+
+	var sb strings.Builder
+	for _, tag := range tags {
+		sb.WriteString(indent + `<locale android:name="` + tag + `"/>` + "\n")
+	}
+
+	updated := s[:insertAt] + sb.String() + s[insertAt:]
+	return []byte(updated), declared, nil
+}
+
+// indentUnit returns a single indentation level matching the given indentation.
+func indentUnit(indent string) string {
+	if indent == "" || strings.Contains(indent, "\t") {
+		return "\t"
+	}
+	return indent
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateInfoPlistLocales(t *testing.T) {
+	type testCase struct {
+		Name             string
+		Input            string
+		Expected         string
+		ExpectedDeclared []string
+	}
+
+	testCases := []testCase{
+		{
+			Name: "replaces existing localizations",
+			Input: "<plist version=\"1.0\">\n<dict>\n\t<key>CFBundleLocalizations</key>\n\t<array>\n" +
+				"\t\t<string>en</string>\n\t\t<string>de</string>\n\t</array>\n\t<key>CFBundleName</key>\n\t<string>app</string>\n</dict>\n</plist>\n",
+			Expected: "<plist version=\"1.0\">\n<dict>\n\t<key>CFBundleLocalizations</key>\n\t<array>\n" +
+				"\t\t<string>en</string>\n\t\t<string>pt-BR</string>\n\t</array>\n\t<key>CFBundleName</key>\n\t<string>app</string>\n</dict>\n</plist>\n",
+			ExpectedDeclared: []string{"en", "de"},
+		},
+		{
+			Name:     "replaces empty array",
+			Input:    "<plist>\n<dict>\n  <key>CFBundleLocalizations</key>\n  <array/>\n</dict>\n</plist>\n",
+			Expected: "<plist>\n<dict>\n  <key>CFBundleLocalizations</key>\n  <array>\n    <string>en</string>\n    <string>pt-BR</string>\n  </array>\n</dict>\n</plist>\n",
+		},
+		{
+			Name:  "adds missing localizations",
+			Input: "<plist>\n<dict>\n\t<key>CFBundleName</key>\n\t<string>app</string>\n</dict>\n</plist>\n",
+			Expected: "<plist>\n<dict>\n\t<key>CFBundleName</key>\n\t<string>app</string>\n\t<key>CFBundleLocalizations</key>\n\t<array>\n" +
+				"\t\t<string>en</string>\n\t\t<string>pt-BR</string>\n\t</array>\n</dict>\n</plist>\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			updated, declared, err := updateInfoPlistLocales([]byte(testCase.Input), []string{"en", "pt-BR"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, string(updated))
+			assert.Equal(t, testCase.ExpectedDeclared, declared)
+		})
+	}
+
+	_, _, err := updateInfoPlistLocales([]byte("<plist></plist>"), []string{"en"})
+	assert.EqualError(t, err, "top-level dict not found")
+}
+
+func TestUpdateAndroidLocales(t *testing.T) {
+	input := `<?xml version="1.0" encoding="utf-8"?>
+<locale-config xmlns:android="http://schemas.android.com/apk/res/android">
+  <!-- Supported locales -->
+  <locale android:name="en"/>
+  <locale android:name="de" />
+</locale-config>
+`
+
+	updated, declared, err := updateAndroidLocales([]byte(input), []string{"en", "pt-BR"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"en", "de"}, declared)
+	assert.Equal(t, `<?xml version="1.0" encoding="utf-8"?>
+<locale-config xmlns:android="http://schemas.android.com/apk/res/android">
+  <!-- Supported locales -->
+  <locale android:name="en"/>
+  <locale android:name="pt-BR"/>
+</locale-config>
+`, string(updated))
+
+	updated, declared, err = updateAndroidLocales([]byte(androidLocalesConfig), []string{"en"})
+
+	assert.NoError(t, err)
+	assert.Nil(t, declared)
+	assert.Equal(t, `<?xml version="1.0" encoding="utf-8"?>
+<locale-config xmlns:android="http://schemas.android.com/apk/res/android">
+    <locale android:name="en"/>
+</locale-config>
+`, string(updated))
+}
+
+func TestSyncPlatformLocales(t *testing.T) {
+	root := t.TempDir()
+	infoPlist := "<plist>\n<dict>\n\t<key>CFBundleLocalizations</key>\n\t<array>\n\t\t<string>en</string>\n\t</array>\n</dict>\n</plist>\n"
+	writeTestFiles(t, root, map[string]string{
+		"lib/l10n/app_en.arb":                         "{}",
+		"lib/l10n/app_pt_br.arb":                      "{}",
+		"lib/l10n/app_zh_hant_tw.arb":                 "{}",
+		"lib/l10n/other.txt":                          "",
+		"ios/Runner/Info.plist":                       infoPlist,
+		"android/app/src/main/res/values/strings.xml": "<resources/>",
+	})
+
+	var logs bytes.Buffer
+	c := &poeCommand{
+		options: &poeOptions{
+			ARBPrefix: "app_",
+			OutputDir: filepath.Join(root, "lib", "l10n"),
+			PlatformLocales: &platformLocalesOptions{
+				IOSDir:        filepath.Join(root, "ios", "Runner"),
+				AndroidResDir: filepath.Join(root, "android", "app", "src", "main", "res"),
+			},
+		},
+		log: log.New(&logs),
+	}
+
+	err := c.SyncPlatformLocales()
+	assert.NoError(t, err)
+
+	plist, err := os.ReadFile(filepath.Join(root, "ios", "Runner", "Info.plist"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(plist), "\t\t<string>en</string>\n\t\t<string>pt-BR</string>\n\t\t<string>zh-Hant-TW</string>\n")
+
+	localesConfig, err := os.ReadFile(filepath.Join(root, "android", "app", "src", "main", "res", "xml", "locales_config.xml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(localesConfig), `<locale android:name="zh-Hant-TW"/>`)
+
+	assert.Contains(t, logs.String(), "Info.plist: added pt-BR, zh-Hant-TW")
+	assert.Contains(t, logs.String(), "locales_config.xml: added en, pt-BR, zh-Hant-TW")
+
+	logs.Reset()
+	err = c.SyncPlatformLocales()
+	assert.NoError(t, err)
+	assert.Contains(t, logs.String(), "Info.plist: up to date")
+	assert.Contains(t, logs.String(), "locales_config.xml: up to date")
+}
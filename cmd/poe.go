@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"os"
-	"path"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/convert/pseudo"
 	"github.com/leancodepl/poe2arb/flutter"
 	"github.com/leancodepl/poe2arb/log"
 	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/leancodepl/poe2arb/source"
 	"github.com/spf13/cobra"
 )
 
@@ -24,7 +30,14 @@ var (
 		SilenceErrors: true,
 		SilenceUsage:  true,
 		RunE:          runPoe,
-		PreRunE:       versionGuard.GetFlutterConfigAndEnsureSufficientVersion,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// In the monorepo mode, each package's Flutter config is loaded separately.
+			if root, _ := cmd.Flags().GetString(monorepoFlag); root != "" {
+				return nil
+			}
+
+			return versionGuard.GetFlutterConfigAndEnsureSufficientVersion(cmd, args)
+		},
 	}
 	termPrefixRegexp = regexp.MustCompile("[a-zA-Z]*")
 )
@@ -33,21 +46,59 @@ const (
 	projectIDFlag     = "project-id"
 	tokenFlag         = "token"
 	termPrefixFlag    = "term-prefix"
+	namingFlag        = "naming"
 	outputDirFlag     = "output-dir"
 	overrideLangsFlag = "langs"
+	concurrencyFlag   = "concurrency"
+
+	placeholderMismatchFlag = "placeholder-mismatch"
+	incompletePluralsFlag   = "incomplete-plurals"
+	minCoverageFlag         = "min-coverage"
+	lowCoverageFlag         = "low-coverage"
+	forceFlag               = "force"
+	fromSnapshotFlag        = "from-snapshot"
+	sourceDirFlag           = "source-dir"
+	iosTermPrefixFlag       = "ios-term-prefix"
+	androidTermPrefixFlag   = "android-term-prefix"
+	syncPlatformLocalesFlag = "sync-platform-locales"
 )
 
 func init() {
 	poeCmd.Flags().StringP(projectIDFlag, "p", "", "POEditor project ID")
 	poeCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
 	poeCmd.Flags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
+	poeCmd.Flags().StringP(namingFlag, "", "",
+		"How term names are turned into message names: keep, dot-to-camel or snake-to-camel [default: keep]")
 	poeCmd.Flags().StringP(outputDirFlag, "o", "", `Output directory [default: "."]`)
 	poeCmd.Flags().StringSliceP(overrideLangsFlag, "", []string{}, "Override downloaded languages")
+	poeCmd.Flags().IntP(concurrencyFlag, "", 0, "Number of languages exported at the same time [default: 1]")
+	poeCmd.Flags().StringP(placeholderMismatchFlag, "", "",
+		"What to do with translations whose placeholders differ from the template: warn, skip or fail [default: warn]")
+	poeCmd.Flags().StringP(incompletePluralsFlag, "", "",
+		"What to do with plurals missing categories required by the language: warn, skip or fail [default: warn]")
+	poeCmd.Flags().Float64P(minCoverageFlag, "", 0, "Minimum percentage of translated messages in a language [default: 0]")
+	poeCmd.Flags().StringP(lowCoverageFlag, "", "",
+		"What to do with languages below the minimum coverage: skip or fail [default: fail]")
+	poeCmd.Flags().String(fromSnapshotFlag, "", "Convert exports saved with the snapshot command, instead of downloading them")
+	poeCmd.Flags().String(sourceDirFlag, "", "Convert POEditor JSON or ARB files from a directory, instead of downloading them")
+	poeCmd.Flags().Bool(forceFlag, false, "Export all languages, even if they haven't changed since the last run")
+	poeCmd.Flags().String(iosTermPrefixFlag, "", "POEditor term prefix of iOS InfoPlist.strings terms")
+	poeCmd.Flags().String(androidTermPrefixFlag, "", "POEditor term prefix of Android strings.xml terms")
+	poeCmd.Flags().Bool(syncPlatformLocalesFlag, false,
+		"Declare exported locales in iOS Info.plist and Android locales_config.xml")
+	poeCmd.Flags().String(pseudoLocaleFlag, "", "Pseudo-locale generated from the template ARB file, e.g. en_XA")
+	poeCmd.Flags().Int(pseudoPaddingFlag, 0,
+		fmt.Sprintf("Percentage pseudo-localized messages are lengthened by [default: %d]", pseudo.DefaultPadding))
+	poeCmd.Flags().String(monorepoFlag, "", "Export every Flutter package with l10n.yaml found in the given directory")
 }
 
 func runPoe(cmd *cobra.Command, args []string) error {
 	log := getLogger(cmd)
 
+	if root, _ := cmd.Flags().GetString(monorepoFlag); root != "" {
+		return runPoeMonorepo(cmd, root)
+	}
+
 	logSub := log.Info("loading options").Sub()
 
 	sel, err := getOptionsSelector(cmd)
@@ -68,29 +119,39 @@ func runPoe(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	log.Info("fetching project languages")
-	langs, err := poeCmd.GetExportLanguages()
-	if err != nil {
-		logSub.Error(err.Error())
+	if err := poeCmd.Export(cmd.Context()); err != nil {
 		return err
 	}
 
-	if err := poeCmd.EnsureOutputDirectory(); err != nil {
+	log.Success("done")
+
+	return nil
+}
+
+func runPoeMonorepo(cmd *cobra.Command, root string) error {
+	log := getLogger(cmd)
+
+	logSub := log.Info("finding Flutter packages in %s", root).Sub()
+
+	envVars, err := newEnvVars()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
 		return err
 	}
 
-	for _, lang := range langs {
-		flutterLocale, err := flutter.ParseLocale(lang.Code)
-		if err != nil {
-			return fmt.Errorf("parsing %s language code: %w", lang.Code, err)
-		}
-
-		template := options.TemplateLocale == flutterLocale
+	pkgs, err := findMonorepoPackages(logSub, root, cmd.Flags(), envVars)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+	if len(pkgs) == 0 {
+		err := fmt.Errorf("no Flutter packages with POEditor project ID found in %s", root)
+		logSub.Error(err.Error())
+		return err
+	}
 
-		err = poeCmd.ExportLanguage(lang, flutterLocale, template)
-		if err != nil {
-			return fmt.Errorf("exporting %s (%s) language: %w", lang.Name, lang.Code, err)
-		}
+	if err := ExportMonorepo(cmd.Context(), log, pkgs); err != nil {
+		return err
 	}
 
 	log.Success("done")
@@ -107,16 +168,35 @@ func getOptionsSelector(cmd *cobra.Command) (*poeOptionsSelector, error) {
 	flutterCfg := flutterConfigFromCommand(cmd)
 
 	return &poeOptionsSelector{
-		flags: cmd.Flags(),
-		l10n:  flutterCfg.L10n,
-		env:   envVars,
+		flags:   cmd.Flags(),
+		l10n:    flutterCfg.L10n,
+		env:     envVars,
+		rootDir: flutterCfg.RootDir,
 	}, nil
 }
 
 type poeCommand struct {
 	options *poeOptions
-	client  *poeditor.Client
 	log     *log.Logger
+
+	// source is the project terms are exported from: POEditor, a snapshot or a local directory.
+	source source.Source
+
+	// templatePlaceholders are set once the template language is converted
+	// and used to validate the other languages.
+	templatePlaceholders map[string][]string
+
+	// exports is set to share downloads between flavors, packages of the same project
+	// and native strings.
+	exports *projectExports
+
+	// nativeFiles are converted native strings files by their paths, written after the ARB files.
+	nativeFiles   map[string][]byte
+	nativeFilesMu sync.Mutex
+
+	// state is the sync state of the last run, updated with the exported languages.
+	state   *syncState
+	stateMu sync.Mutex
 }
 
 func NewPoeCommand(options *poeOptions, log *log.Logger) (*poeCommand, error) {
@@ -128,35 +208,194 @@ func NewPoeCommand(options *poeOptions, log *log.Logger) (*poeCommand, error) {
 		return nil, errors.New(msg)
 	}
 
-	client := poeditor.NewClient(options.Token)
+	src, err := newSource(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var exports *projectExports
+	if len(options.Flavors) > 0 || len(options.NativeStrings) > 0 {
+		exports = newProjectExports()
+	}
 
 	return &poeCommand{
 		options: options,
-		client:  client,
 		log:     log,
+		source:  src,
+		exports: exports,
 	}, nil
 }
 
+// newSource returns the source selected by the options, POEditor by default.
+func newSource(options *poeOptions) (source.Source, error) {
+	switch {
+	case options.FromSnapshot != "":
+		return openSnapshot(options.FromSnapshot)
+
+	case options.SourceDir != "":
+		return source.NewDir(options.SourceDir, &source.DirOptions{
+			TemplateLocale: options.TemplateLocale,
+			TermPrefix:     options.TermPrefix,
+			UseEscaping:    options.UseEscaping,
+			StrictSyntax:   options.StrictSyntax,
+		}), nil
+	}
+
+	return poeditor.NewProject(poeditor.NewClient(options.Token), options.ProjectID), nil
+}
+
 func validatePoeOptions(options *poeOptions) []error {
 	errs := []error{}
 
-	if options.ProjectID == "" {
-		errs = append(errs, errors.New("no POEditor project id provided"))
+	// Snapshots and source directories are converted without accessing POEditor.
+	if options.FromSnapshot != "" && options.SourceDir != "" {
+		errs = append(errs, errors.New("snapshot and source directory can't be used together"))
+	} else if options.FromSnapshot == "" && options.SourceDir == "" {
+		if options.ProjectID == "" {
+			errs = append(errs, errors.New("no POEditor project id provided"))
+		}
+
+		if options.Token == "" {
+			errs = append(errs, errors.New("no POEditor API token provided"))
+		}
+	}
+
+	if options.Concurrency < 1 {
+		errs = append(errs, errors.New("concurrency must be at least 1"))
 	}
 
-	if options.Token == "" {
-		errs = append(errs, errors.New("no POEditor API token provided"))
+	if !slices.Contains(poe2arb.PlaceholderMismatchModes, options.PlaceholderMismatch) {
+		errs = append(errs, fmt.Errorf("invalid placeholder mismatch mode %q, must be one of: warn, skip, fail", options.PlaceholderMismatch))
+	}
+
+	if !slices.Contains(poe2arb.NamingStrategies, options.Naming) {
+		errs = append(errs, fmt.Errorf("invalid naming strategy %q, must be one of: keep, dot-to-camel, snake-to-camel", options.Naming))
+	}
+
+	if !slices.Contains(poe2arb.IncompletePluralModes, options.IncompletePlurals) {
+		errs = append(errs, fmt.Errorf("invalid incomplete plurals mode %q, must be one of: warn, skip, fail", options.IncompletePlurals))
+	}
+
+	if options.MinCoverage < 0 || options.MinCoverage > 100 {
+		errs = append(errs, errors.New("minimum coverage must be between 0 and 100"))
+	}
+
+	if !slices.Contains(lowCoverageModes, options.LowCoverage) {
+		errs = append(errs, fmt.Errorf("invalid low coverage mode %q, must be one of: skip, fail", options.LowCoverage))
 	}
 
 	if !termPrefixRegexp.MatchString(options.TermPrefix) {
 		errs = append(errs, errors.New("term prefix must contain only letters or be empty"))
 	}
 
+	for _, native := range options.NativeStrings {
+		if termPrefixRegexp.FindString(native.TermPrefix) != native.TermPrefix {
+			errs = append(errs, fmt.Errorf("%s term prefix must contain only letters", native.Platform))
+		} else if native.TermPrefix == options.TermPrefix {
+			errs = append(errs, fmt.Errorf("%s term prefix %q is the same as the base one", native.Platform, native.TermPrefix))
+		}
+	}
+
+	if options.Pseudo != nil && options.Pseudo.Locale == options.TemplateLocale {
+		errs = append(errs, fmt.Errorf("pseudo-locale %s is the same as the template one", options.Pseudo.Locale))
+	}
+
+	for _, flavor := range options.Flavors {
+		if len(flavor.TermPrefixes) == 0 {
+			errs = append(errs, fmt.Errorf("flavor %s has no term prefixes", flavor.Name))
+		}
+
+		for _, prefix := range flavor.TermPrefixes {
+			if prefix == "" || termPrefixRegexp.FindString(prefix) != prefix {
+				errs = append(errs, fmt.Errorf("flavor %s term prefix %q must contain only letters", flavor.Name, prefix))
+			} else if prefix == options.TermPrefix {
+				errs = append(errs, fmt.Errorf("flavor %s term prefix %q is the same as the base one", flavor.Name, prefix))
+			}
+		}
+	}
+
 	return errs
 }
 
+// Export exports the project languages to the output directory, along with every flavor.
+// The ARB files of the base and all flavors are replaced together, only if every one of them succeeded.
+// The pseudo-locale is generated from the exported template ARB file.
+func (c *poeCommand) Export(ctx context.Context) error {
+	logSub := c.log.Info("fetching project languages").Sub()
+	langs, err := c.GetExportLanguages()
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	commands := []*poeCommand{c}
+	for _, flavor := range c.options.Flavors {
+		flavorLog := c.log.Info("exporting %s flavor to %s", flavor.Name, flavor.OutputDir).Sub()
+		commands = append(commands, c.flavorCommand(flavor, flavorLog))
+	}
+
+	var stagings []*stagingDir
+	defer func() {
+		for _, staging := range stagings {
+			staging.Discard()
+		}
+	}()
+
+	for _, command := range commands {
+		if err := command.EnsureOutputDirectory(); err != nil {
+			return err
+		}
+
+		staging, err := command.StageLanguages(ctx, langs)
+		if err != nil {
+			if len(commands) > 1 {
+				c.log.Error("no ARB files of any flavor were changed")
+			}
+			return err
+		}
+		if staging != nil {
+			stagings = append(stagings, staging)
+		}
+	}
+
+	if len(stagings) > 0 {
+		logSub := c.log.Info("saving ARB files").Sub()
+		if err := commitStagingDirs(stagings); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	for _, command := range commands {
+		if err := command.finishExport(); err != nil {
+			return err
+		}
+
+		if err := command.GeneratePseudoLocale(); err != nil {
+			return err
+		}
+
+		if err := command.SyncPlatformLocales(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flavorCommand returns a command exporting the given flavor. Project languages and exports
+// are shared with it, so that they're downloaded only once.
+func (c *poeCommand) flavorCommand(flavor *flavorOptions, log *log.Logger) *poeCommand {
+	return &poeCommand{
+		options: c.options.forFlavor(flavor),
+		log:     log,
+		source:  c.source,
+		exports: c.exports,
+	}
+}
+
 func (c *poeCommand) GetExportLanguages() ([]poeditor.Language, error) {
-	langs, err := c.client.GetProjectLanguages(c.options.ProjectID)
+	langs, err := c.getProjectLanguages()
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +433,14 @@ func (c *poeCommand) GetExportLanguages() ([]poeditor.Language, error) {
 	return langs, nil
 }
 
+func (c *poeCommand) getProjectLanguages() ([]poeditor.Language, error) {
+	if c.exports != nil {
+		return c.exports.Languages(c.source.Languages)
+	}
+
+	return c.source.Languages()
+}
+
 func (c *poeCommand) EnsureOutputDirectory() error {
 	dir := c.options.OutputDir
 	if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -207,42 +454,408 @@ func (c *poeCommand) EnsureOutputDirectory() error {
 	return nil
 }
 
-func (c *poeCommand) ExportLanguage(lang poeditor.Language, flutterLocale flutter.Locale, template bool) error {
-	logSub := c.log.Info("fetching JSON export for %s (%s)", lang.Name, lang.Code).Sub()
-	url, err := c.client.GetExportURL(c.options.ProjectID, lang.Code)
+// ExportLanguages exports given languages using a pool of options.Concurrency workers.
+// ARB files are converted into a staging directory first and moved to the output
+// directory only if every language succeeded. Otherwise, the existing ARB files
+// are left untouched.
+//
+// Languages that haven't changed since the last run are skipped, see syncState.
+func (c *poeCommand) ExportLanguages(ctx context.Context, langs []poeditor.Language) error {
+	staging, err := c.StageLanguages(ctx, langs)
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.Get(url)
+	if staging != nil {
+		defer staging.Discard()
+
+		logSub := c.log.Info("saving ARB files to %s", c.options.OutputDir).Sub()
+		if err := staging.Commit(); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	return c.finishExport()
+}
+
+// StageLanguages converts given languages into a staging directory, to be committed
+// to the output directory. Returns nil if all languages are unchanged since the last run.
+func (c *poeCommand) StageLanguages(ctx context.Context, langs []poeditor.Language) (*stagingDir, error) {
+	c.cleanStagingDirs(c.options.OutputDir)
+
+	c.state = c.loadState()
+
+	langs = c.changedLanguages(langs)
+	if len(langs) == 0 {
+		c.log.Success("all languages are unchanged since the last run")
+		return nil, nil
+	}
+
+	staging, err := newStagingDir(c.options.OutputDir)
 	if err != nil {
-		logSub.Error("making HTTP request failed: " + err.Error())
-		return fmt.Errorf("making HTTP request for export: %w", err)
+		c.log.Error("creating staging directory failed: " + err.Error())
+		return nil, err
 	}
 
-	filePath := path.Join(c.options.OutputDir, fmt.Sprintf("%s%s.arb", c.options.ARBPrefix, flutterLocale.StringFilename()))
-	file, err := os.Create(filePath)
+	err = c.forEachLanguage(ctx, langs, func(ctx context.Context, log *log.Logger, lang poeditor.Language, flutterLocale flutter.Locale, template bool) error {
+		return c.ExportLanguage(ctx, log, lang, flutterLocale, template, staging)
+	})
 	if err != nil {
-		logSub.Error("creating file failed: " + err.Error())
-		return fmt.Errorf("creating ARB file: %w", err)
+		staging.Discard()
+		c.log.Error("no ARB files were changed")
+		return nil, err
 	}
-	defer file.Close()
+
+	return staging, nil
+}
+
+// cleanStagingDirs removes staging directories left in dir by interrupted runs,
+// so that they don't pile up next to the exported files. See cleanStagingDirs.
+func (c *poeCommand) cleanStagingDirs(dir string) {
+	restored, err := cleanStagingDirs(dir)
+	if len(restored) > 0 {
+		c.log.Warning("restored %s left by an interrupted run", strings.Join(restored, ", "))
+	}
+	if err != nil {
+		c.log.Warning("cleaning up staging directories failed: " + err.Error())
+	}
+}
+
+// finishExport writes the files depending on the committed ARB files and saves the sync state.
+func (c *poeCommand) finishExport() error {
+	if err := c.WriteNativeStrings(); err != nil {
+		return err
+	}
+
+	c.saveState()
+
+	return nil
+}
+
+// loadState loads the sync state of the last run, or returns an empty one if it's not available.
+func (c *poeCommand) loadState() *syncState {
+	if c.options.StatePath == "" {
+		return newSyncState()
+	}
+
+	state, err := loadSyncState(c.options.StatePath)
+	if err != nil {
+		c.log.Warning("ignoring sync state: " + err.Error())
+		return newSyncState()
+	}
+
+	return state
+}
+
+func (c *poeCommand) saveState() {
+	if c.options.StatePath == "" {
+		return
+	}
+
+	c.state.Options = optionsFingerprint(c.options)
+	if err := c.state.save(c.options.StatePath); err != nil {
+		c.log.Warning("saving sync state failed: " + err.Error())
+	}
+}
+
+// changedLanguages returns the languages that changed since the last run, or all of them
+// if the export is forced. The template language is returned whenever any other language is,
+// as they are validated against it.
+func (c *poeCommand) changedLanguages(langs []poeditor.Language) []poeditor.Language {
+	if c.options.Force {
+		return langs
+	}
+
+	options := optionsFingerprint(c.options)
+	unchanged := func(lang poeditor.Language) bool {
+		return c.state.unchanged(options, lang, c.options.OutputDir)
+	}
+
+	var changed []poeditor.Language
+	var template *poeditor.Language
+	for _, lang := range langs {
+		if flutterLocale, err := flutter.ParseLocale(lang.Code); err == nil && flutterLocale == c.options.TemplateLocale {
+			template = &lang
+			continue
+		}
+
+		if unchanged(lang) {
+			c.log.Info("%s (%s) unchanged since the last run, skipping", lang.Name, lang.Code)
+			continue
+		}
+
+		changed = append(changed, lang)
+	}
+
+	if template != nil {
+		if len(changed) > 0 || !unchanged(*template) {
+			changed = append([]poeditor.Language{*template}, changed...)
+		} else {
+			c.log.Info("%s (%s) unchanged since the last run, skipping", template.Name, template.Code)
+		}
+	}
+
+	return changed
+}
+
+type languageFunc func(ctx context.Context, log *log.Logger, lang poeditor.Language, flutterLocale flutter.Locale, template bool) error
+
+// forEachLanguage calls fn for every language using a pool of options.Concurrency workers.
+// The template language is processed first, so that the others can be validated against it.
+// The first failure cancels the remaining languages. Each language that failed is reported.
+func (c *poeCommand) forEachLanguage(ctx context.Context, langs []poeditor.Language, fn languageFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	errs := make([]error, len(langs))
+
+	templateIndex := slices.IndexFunc(langs, func(lang poeditor.Language) bool {
+		flutterLocale, err := flutter.ParseLocale(lang.Code)
+		return err == nil && flutterLocale == c.options.TemplateLocale
+	})
+	if templateIndex != -1 {
+		if err := c.runLanguageGrouped(ctx, langs[templateIndex], fn); err != nil {
+			errs[templateIndex] = err
+			cancel()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range min(c.options.Concurrency, len(langs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if err := c.runLanguageGrouped(ctx, langs[i], fn); err != nil {
+					errs[i] = err
+					cancel()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range langs {
+		if i == templateIndex {
+			continue
+		}
+
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			failed = append(failed, err)
+		}
+	}
+
+	if len(failed) > 0 {
+		logSub := c.log.Error("failed exporting %d of %d languages", len(failed), len(langs)).Sub()
+		for _, err := range failed {
+			logSub.Error(err.Error())
+		}
+
+		return errors.Join(failed...)
+	}
+
+	return nil
+}
+
+// runLanguageGrouped runs fn for a single language, keeping its log output
+// together even if other languages are processed at the same time.
+func (c *poeCommand) runLanguageGrouped(ctx context.Context, lang poeditor.Language, fn languageFunc) error {
+	log := c.log.Buffered()
+	defer log.Flush()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	flutterLocale, err := flutter.ParseLocale(lang.Code)
+	if err != nil {
+		log.Error("parsing %s language code failed: %s", lang.Code, err)
+		return fmt.Errorf("parsing %s language code: %w", lang.Code, err)
+	}
+
+	template := c.options.TemplateLocale == flutterLocale
+
+	err = fn(ctx, log, lang, flutterLocale, template)
+	if err != nil {
+		return fmt.Errorf("exporting %s (%s) language: %w", lang.Name, lang.Code, err)
+	}
+
+	return nil
+}
+
+// ExportLanguage fetches and converts a single language, saving the ARB to the staging directory.
+// Languages below the minimum coverage are not saved, see acceptCoverage. If they're skipped,
+// their existing ARB files are removed, so that outdated translations aren't shipped.
+func (c *poeCommand) ExportLanguage(
+	ctx context.Context,
+	log *log.Logger,
+	lang poeditor.Language,
+	flutterLocale flutter.Locale,
+	template bool,
+	staging *stagingDir,
+) error {
+	var b bytes.Buffer
+	coverage, err := c.ConvertLanguage(ctx, log, lang, flutterLocale, template, &b)
+	if err != nil {
+		return err
+	}
+
+	fileName := c.arbFileName(flutterLocale)
+
+	if ok, err := c.acceptCoverage(log, coverage, template); !ok {
+		if err == nil {
+			c.removeLanguage(log, lang, fileName, staging)
+		}
+		return err
+	}
+
+	if err := c.ConvertNativeStrings(ctx, log, lang, flutterLocale, template); err != nil {
+		return err
+	}
+
+	c.recordState(lang, fileName, b.Bytes())
+
+	// Leave identical files untouched, keeping their modification time.
+	current, err := os.ReadFile(filepath.Join(c.options.OutputDir, fileName))
+	if err == nil && bytes.Equal(current, b.Bytes()) {
+		log.Info("%s is up to date", fileName)
+		return nil
+	}
+
+	if err := os.WriteFile(staging.Path(fileName), b.Bytes(), 0o666); err != nil {
+		log.Error("writing file failed: " + err.Error())
+		return fmt.Errorf("writing ARB file: %w", err)
+	}
+
+	return nil
+}
+
+// removeLanguage removes the ARB file of a skipped language on commit, along with its sync state.
+func (c *poeCommand) removeLanguage(log *log.Logger, lang poeditor.Language, fileName string, staging *stagingDir) {
+	if _, err := os.Stat(filepath.Join(c.options.OutputDir, fileName)); err == nil {
+		log.Info("removing %s", fileName)
+		staging.Remove(fileName)
+	}
+
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	delete(c.state.Languages, lang.Code)
+}
+
+func (c *poeCommand) recordState(lang poeditor.Language, fileName string, contents []byte) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+
+	c.state.Languages[lang.Code] = &languageState{
+		Updated:      lang.Updated,
+		Translations: lang.Translations,
+		Percentage:   lang.Percentage,
+		File:         fileName,
+		Hash:         hashContents(contents),
+	}
+}
+
+// ConvertLanguage fetches the JSON export of a single language and writes it converted to ARB.
+// Returns how much of the language is translated.
+func (c *poeCommand) ConvertLanguage(
+	ctx context.Context,
+	log *log.Logger,
+	lang poeditor.Language,
+	flutterLocale flutter.Locale,
+	template bool,
+	output io.Writer,
+) (*poe2arb.Coverage, error) {
+	logSub := log.Info("fetching JSON export for %s (%s)", lang.Name, lang.Code).Sub()
+	export, err := c.FetchExport(ctx, logSub, lang.Code)
+	if err != nil {
+		return nil, err
+	}
+	defer export.Close()
 
 	convertLogSub := logSub.Info("converting JSON to ARB").Sub()
 
-	conv := poe2arb.NewConverter(resp.Body, &poe2arb.ConverterOptions{
+	conv := poe2arb.NewConverter(export, &poe2arb.ConverterOptions{
 		Locale:                    flutterLocale,
 		Template:                  template,
 		RequireResourceAttributes: c.options.RequireResourceAttributes,
 		TermPrefix:                c.options.TermPrefix,
+		OverridePrefixes:          c.options.OverridePrefixes,
+		Naming:                    c.options.Naming,
+		UseEscaping:               c.options.UseEscaping,
+		StrictSyntax:              c.options.StrictSyntax,
+		TemplatePlaceholders:      c.templatePlaceholders,
+		PlaceholderMismatch:       c.options.PlaceholderMismatch,
+		IncompletePlurals:         c.options.IncompletePlurals,
 	})
-	err = conv.Convert(file)
+	err = conv.Convert(output)
 	if err != nil {
-		convertLogSub.Error(err.Error())
-		return err
+		if !errors.Is(err, context.Canceled) {
+			convertLogSub.Error(err.Error())
+		}
+		return nil, err
 	}
 
-	logSub.Success("saved to %s", filePath)
+	if template {
+		c.templatePlaceholders = conv.Placeholders()
+	}
 
-	return nil
+	if mismatches := conv.PlaceholderMismatches(); len(mismatches) > 0 {
+		mismatchLogSub := convertLogSub.Warning("%d messages with placeholders different from template", len(mismatches)).Sub()
+		for _, mismatch := range mismatches {
+			mismatchLogSub.Warning(mismatch.Error())
+		}
+		if c.options.PlaceholderMismatch == poe2arb.PlaceholderMismatchSkip {
+			convertLogSub.Warning("skipped these messages, template translations will be used instead")
+		}
+	}
+
+	if incomplete := conv.IncompletePlurals(); len(incomplete) > 0 {
+		incompleteLogSub := convertLogSub.Warning("%d plural messages with missing plural categories", len(incomplete)).Sub()
+		for _, plural := range incomplete {
+			incompleteLogSub.Warning(plural.Error())
+		}
+		if c.options.IncompletePlurals == poe2arb.IncompletePluralSkip {
+			convertLogSub.Warning("skipped these messages")
+		}
+	}
+
+	reportCoverage(convertLogSub, lang, conv.Coverage())
+
+	logSub.Success("converted")
+
+	return conv.Coverage(), nil
+}
+
+// FetchExport returns the JSON export of a single language, either fetched from the source
+// or shared with other packages of the monorepo.
+func (c *poeCommand) FetchExport(ctx context.Context, log *log.Logger, langCode string) (io.ReadCloser, error) {
+	fetch := func() (io.ReadCloser, error) {
+		export, err := c.source.Export(ctx, langCode)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			log.Error("fetching export failed: " + err.Error())
+		}
+		return export, err
+	}
+
+	if c.exports != nil {
+		return c.exports.Export(langCode, fetch)
+	}
+
+	return fetch()
 }
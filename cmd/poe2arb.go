@@ -20,9 +20,14 @@ var (
 type loggerKey struct{}
 
 func Execute(logger *log.Logger) {
+	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(convertCmd)
 	rootCmd.AddCommand(poeCmd)
+	rootCmd.AddCommand(pseudoCmd)
+	rootCmd.AddCommand(pushCmd)
 	rootCmd.AddCommand(seedCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(usageCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	ctx := context.WithValue(context.Background(), loggerKey{}, logger)
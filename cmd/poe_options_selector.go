@@ -4,8 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/convert/poe2native"
+	"github.com/leancodepl/poe2arb/convert/pseudo"
 	"github.com/leancodepl/poe2arb/flutter"
 	"github.com/spf13/pflag"
 	"golang.org/x/text/language"
@@ -14,9 +19,10 @@ import (
 // poeOptionsSelector decides on the correct values for given options
 // depending on the available sources.
 type poeOptionsSelector struct {
-	flags *pflag.FlagSet
-	l10n  *flutter.L10n
-	env   *envVars
+	flags   *pflag.FlagSet
+	l10n    *flutter.L10n
+	env     *envVars
+	rootDir string
 }
 
 // poeOptions describes options passed or otherwise obtained to the poe command.
@@ -24,12 +30,70 @@ type poeOptions struct {
 	ProjectID  string
 	Token      string
 	TermPrefix string
+	// OverridePrefixes are term prefixes layered on top of TermPrefix, set for flavors.
+	OverridePrefixes []string
+	Naming           poe2arb.NamingStrategy
 
 	ARBPrefix                 string
 	TemplateLocale            flutter.Locale
 	OutputDir                 string
 	OverrideLangs             []string
 	RequireResourceAttributes bool
+	UseEscaping               bool
+	StrictSyntax              bool
+	Concurrency               int
+	PlaceholderMismatch       poe2arb.PlaceholderMismatchMode
+	IncompletePlurals         poe2arb.IncompletePluralMode
+	MinCoverage               float64
+	LowCoverage               lowCoverageMode
+
+	// StatePath is the sync state file, empty if it's not used. See syncState.
+	StatePath string
+	// Force exports all languages, regardless of the sync state.
+	Force bool
+	// FromSnapshot is the snapshot directory exports are read from, instead of POEditor.
+	FromSnapshot string
+	// SourceDir is the directory of POEditor JSON or ARB files terms are read from, instead of POEditor.
+	SourceDir string
+
+	// Flavors are exported after the base ARB files, each to its own output directory.
+	Flavors []*flavorOptions
+	// NativeStrings are platforms native strings files are generated for.
+	NativeStrings []*nativeStringsOptions
+	// PlatformLocales are platform files declaring the exported locales, nil if they aren't updated.
+	PlatformLocales *platformLocalesOptions
+	// Pseudo is the pseudo-locale generated from the template ARB file, nil if it's not generated.
+	Pseudo *pseudoOptions
+}
+
+// flavorOptions describes a flavor, whose ARB files have its term prefixes
+// layered on top of the base term prefix.
+type flavorOptions struct {
+	Name         string
+	TermPrefixes []string
+	OutputDir    string
+}
+
+// forFlavor returns the options used to export the given flavor.
+func (o *poeOptions) forFlavor(flavor *flavorOptions) *poeOptions {
+	options := *o
+	options.OverridePrefixes = flavor.TermPrefixes
+	options.OutputDir = flavor.OutputDir
+	options.Flavors = nil
+	options.NativeStrings = nil
+	options.PlatformLocales = nil
+	options.Pseudo = nil
+
+	if o.StatePath != "" {
+		options.StatePath = filepath.Join(filepath.Dir(o.StatePath), "state-"+flavor.Name+".json")
+	}
+
+	return &options
+}
+
+// syntax returns the ICU syntax options of ARB messages and POEditor translations.
+func (o *poeOptions) syntax() icu.Options {
+	return icu.Options{Escaping: o.UseEscaping, Relaxed: !o.StrictSyntax}
 }
 
 // SelectOptions selects all the options used for the poe command.
@@ -49,6 +113,11 @@ func (s *poeOptionsSelector) SelectOptions() (*poeOptions, error) {
 		return nil, err
 	}
 
+	naming, err := s.SelectNaming()
+	if err != nil {
+		return nil, err
+	}
+
 	arbPrefix, templateLocale, err := s.SelectARBPrefixAndTemplate()
 	if err != nil {
 		return nil, err
@@ -65,16 +134,91 @@ func (s *poeOptionsSelector) SelectOptions() (*poeOptions, error) {
 	}
 
 	requireResourceAttributes := s.SelectRequireResourceAttributes()
+	useEscaping := s.SelectUseEscaping()
+	strictSyntax := s.SelectStrictSyntax()
+
+	concurrency, err := s.SelectConcurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	placeholderMismatch, err := s.SelectPlaceholderMismatch()
+	if err != nil {
+		return nil, err
+	}
+
+	incompletePlurals, err := s.SelectIncompletePlurals()
+	if err != nil {
+		return nil, err
+	}
+
+	minCoverage, err := s.SelectMinCoverage()
+	if err != nil {
+		return nil, err
+	}
+
+	lowCoverage, err := s.SelectLowCoverage()
+	if err != nil {
+		return nil, err
+	}
+
+	force, err := s.SelectForce()
+	if err != nil {
+		return nil, err
+	}
+
+	fromSnapshot, err := s.SelectFromSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDir, err := s.SelectSourceDir()
+	if err != nil {
+		return nil, err
+	}
+
+	flavors := s.SelectFlavors(outputDir)
+
+	nativeStrings, err := s.SelectNativeStrings()
+	if err != nil {
+		return nil, err
+	}
+
+	platformLocales, err := s.SelectPlatformLocales()
+	if err != nil {
+		return nil, err
+	}
+
+	pseudoLocale, err := s.SelectPseudo()
+	if err != nil {
+		return nil, err
+	}
 
 	return &poeOptions{
 		ProjectID:                 projectID,
 		Token:                     token,
 		TermPrefix:                termPrefix,
+		Naming:                    naming,
 		ARBPrefix:                 arbPrefix,
 		TemplateLocale:            templateLocale,
 		OutputDir:                 outputDir,
 		OverrideLangs:             overrideLangs,
 		RequireResourceAttributes: requireResourceAttributes,
+		UseEscaping:               useEscaping,
+		StrictSyntax:              strictSyntax,
+		Concurrency:               concurrency,
+		PlaceholderMismatch:       placeholderMismatch,
+		IncompletePlurals:         incompletePlurals,
+		MinCoverage:               minCoverage,
+		LowCoverage:               lowCoverage,
+		StatePath:                 s.SelectStatePath(),
+		Force:                     force,
+		FromSnapshot:              fromSnapshot,
+		SourceDir:                 sourceDir,
+		Flavors:                   flavors,
+		NativeStrings:             nativeStrings,
+		PlatformLocales:           platformLocales,
+		Pseudo:                    pseudoLocale,
 	}, nil
 }
 
@@ -117,6 +261,27 @@ func (s *poeOptionsSelector) SelectTermPrefix() (string, error) {
 	return s.l10n.POEditorTermPrefix, nil
 }
 
+// SelectNaming returns how term names are turned into message names.
+//
+// Defaults to keep.
+func (s *poeOptionsSelector) SelectNaming() (poe2arb.NamingStrategy, error) {
+	if s.flagDefined(namingFlag) {
+		fromCmd, err := s.flags.GetString(namingFlag)
+		if err != nil {
+			return "", err
+		}
+		if fromCmd != "" {
+			return poe2arb.NamingStrategy(fromCmd), nil
+		}
+	}
+
+	if s.l10n.POEditorNaming != "" {
+		return poe2arb.NamingStrategy(s.l10n.POEditorNaming), nil
+	}
+
+	return poe2arb.NamingKeep, nil
+}
+
 // SelectARBPrefix returns ARB files prefix option from available sources.
 func (s *poeOptionsSelector) SelectARBPrefixAndTemplate() (prefix string, templateLocale flutter.Locale, err error) {
 	prefix, err = prefixFromTemplateFileName(s.l10n.TemplateArbFile)
@@ -181,12 +346,14 @@ func (s *poeOptionsSelector) SelectOutputDir() (string, error) {
 //
 // Defaults to empty, which doesn't change the original language list.
 func (s *poeOptionsSelector) SelectOverrideLangs() ([]string, error) {
-	fromCmd, err := s.flags.GetStringSlice(overrideLangsFlag)
-	if err != nil {
-		return nil, err
-	}
-	if len(fromCmd) > 0 {
-		return fromCmd, nil
+	if s.flagDefined(overrideLangsFlag) {
+		fromCmd, err := s.flags.GetStringSlice(overrideLangsFlag)
+		if err != nil {
+			return nil, err
+		}
+		if len(fromCmd) > 0 {
+			return fromCmd, nil
+		}
 	}
 
 	fromL10n := s.l10n.POEditorLangs
@@ -204,3 +371,287 @@ func (s *poeOptionsSelector) SelectRequireResourceAttributes() bool {
 	// In Flutter, defaults to false, so no need to handle lack of the option.
 	return s.l10n.RequireResourceAttributes
 }
+
+// SelectUseEscaping returns whether apostrophes quote literal text in messages.
+func (s *poeOptionsSelector) SelectUseEscaping() bool {
+	// In Flutter, defaults to false, so no need to handle lack of the option.
+	return s.l10n.UseEscaping
+}
+
+// SelectStrictSyntax returns whether brackets which don't start a placeholder
+// and invalid translations are reported as errors.
+//
+// Defaults to false, which keeps such brackets as text.
+func (s *poeOptionsSelector) SelectStrictSyntax() bool {
+	return s.l10n.POEditorStrictSyntax
+}
+
+// SelectConcurrency returns the number of languages exported at the same time.
+//
+// Defaults to 1, which exports languages one by one.
+func (s *poeOptionsSelector) SelectConcurrency() (int, error) {
+	if s.flagDefined(concurrencyFlag) {
+		fromCmd, err := s.flags.GetInt(concurrencyFlag)
+		if err != nil {
+			return 0, err
+		}
+		if fromCmd != 0 {
+			return fromCmd, nil
+		}
+	}
+
+	if s.l10n.POEditorConcurrency != 0 {
+		return s.l10n.POEditorConcurrency, nil
+	}
+
+	return 1, nil
+}
+
+// SelectPlaceholderMismatch returns what to do with translations whose placeholders
+// differ from the template ones.
+//
+// Defaults to warn.
+func (s *poeOptionsSelector) SelectPlaceholderMismatch() (poe2arb.PlaceholderMismatchMode, error) {
+	if s.flagDefined(placeholderMismatchFlag) {
+		fromCmd, err := s.flags.GetString(placeholderMismatchFlag)
+		if err != nil {
+			return "", err
+		}
+		if fromCmd != "" {
+			return poe2arb.PlaceholderMismatchMode(fromCmd), nil
+		}
+	}
+
+	if s.l10n.POEditorPlaceholderMismatch != "" {
+		return poe2arb.PlaceholderMismatchMode(s.l10n.POEditorPlaceholderMismatch), nil
+	}
+
+	return poe2arb.PlaceholderMismatchWarn, nil
+}
+
+// SelectIncompletePlurals returns what to do with plural translations missing
+// categories required by their language.
+//
+// Defaults to warn.
+func (s *poeOptionsSelector) SelectIncompletePlurals() (poe2arb.IncompletePluralMode, error) {
+	if s.flagDefined(incompletePluralsFlag) {
+		fromCmd, err := s.flags.GetString(incompletePluralsFlag)
+		if err != nil {
+			return "", err
+		}
+		if fromCmd != "" {
+			return poe2arb.IncompletePluralMode(fromCmd), nil
+		}
+	}
+
+	if s.l10n.POEditorIncompletePlurals != "" {
+		return poe2arb.IncompletePluralMode(s.l10n.POEditorIncompletePlurals), nil
+	}
+
+	return poe2arb.IncompletePluralWarn, nil
+}
+
+// SelectMinCoverage returns the minimum percentage of translated messages in a language.
+//
+// Defaults to 0, which exports all languages.
+func (s *poeOptionsSelector) SelectMinCoverage() (float64, error) {
+	// Zero exports all languages, so only an explicitly passed flag overrides l10n.yaml.
+	if s.flagDefined(minCoverageFlag) && s.flags.Changed(minCoverageFlag) {
+		return s.flags.GetFloat64(minCoverageFlag)
+	}
+
+	return s.l10n.POEditorMinCoverage, nil
+}
+
+// SelectLowCoverage returns what to do with languages below the minimum coverage.
+//
+// Defaults to fail.
+func (s *poeOptionsSelector) SelectLowCoverage() (lowCoverageMode, error) {
+	if s.flagDefined(lowCoverageFlag) {
+		fromCmd, err := s.flags.GetString(lowCoverageFlag)
+		if err != nil {
+			return "", err
+		}
+		if fromCmd != "" {
+			return lowCoverageMode(fromCmd), nil
+		}
+	}
+
+	if s.l10n.POEditorLowCoverage != "" {
+		return lowCoverageMode(s.l10n.POEditorLowCoverage), nil
+	}
+
+	return lowCoverageFail, nil
+}
+
+// SelectStatePath returns the path of the sync state file, kept in the Flutter project's
+// .dart_tool directory. Empty if the project root is unknown.
+func (s *poeOptionsSelector) SelectStatePath() string {
+	if s.rootDir == "" {
+		return ""
+	}
+
+	return filepath.Join(s.rootDir, ".dart_tool", "poe2arb", "state.json")
+}
+
+// SelectForce returns whether all languages should be exported, regardless of the sync state.
+func (s *poeOptionsSelector) SelectForce() (bool, error) {
+	if !s.flagDefined(forceFlag) {
+		return false, nil
+	}
+
+	return s.flags.GetBool(forceFlag)
+}
+
+// SelectFromSnapshot returns the snapshot directory to convert, or empty to download the exports.
+func (s *poeOptionsSelector) SelectFromSnapshot() (string, error) {
+	if !s.flagDefined(fromSnapshotFlag) {
+		return "", nil
+	}
+
+	return s.flags.GetString(fromSnapshotFlag)
+}
+
+// SelectSourceDir returns the directory used as the translation source, or empty to use POEditor.
+func (s *poeOptionsSelector) SelectSourceDir() (string, error) {
+	if !s.flagDefined(sourceDirFlag) {
+		return "", nil
+	}
+
+	return s.flags.GetString(sourceDirFlag)
+}
+
+// SelectFlavors returns flavors sorted by their names. Their output directories
+// default to subdirectories of the base output directory named after them.
+func (s *poeOptionsSelector) SelectFlavors(outputDir string) []*flavorOptions {
+	var flavors []*flavorOptions
+	for name, flavor := range s.l10n.POEditorFlavors {
+		flavorOutputDir := filepath.Join(outputDir, name)
+		var termPrefixes []string
+		if flavor != nil {
+			termPrefixes = flavor.TermPrefixes
+			if flavor.ARBDir != "" {
+				flavorOutputDir = flavor.ARBDir
+			}
+		}
+
+		flavors = append(flavors, &flavorOptions{
+			Name:         name,
+			TermPrefixes: termPrefixes,
+			OutputDir:    flavorOutputDir,
+		})
+	}
+
+	slices.SortFunc(flavors, func(a, b *flavorOptions) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return flavors
+}
+
+// SelectNativeStrings returns platforms whose native strings are generated,
+// the ones with a term prefix set. Their directories are relative to the project root.
+func (s *poeOptionsSelector) SelectNativeStrings() ([]*nativeStringsOptions, error) {
+	platforms := []struct {
+		platform   poe2native.Platform
+		flag       string
+		termPrefix string
+		dir        string
+	}{
+		{poe2native.PlatformIOS, iosTermPrefixFlag, s.l10n.POEditorIOSTermPrefix, s.l10n.POEditorIOSDir},
+		{poe2native.PlatformAndroid, androidTermPrefixFlag, s.l10n.POEditorAndroidTermPrefix, s.l10n.POEditorAndroidResDir},
+	}
+
+	var nativeStrings []*nativeStringsOptions
+	for _, p := range platforms {
+		termPrefix := p.termPrefix
+		if s.flagDefined(p.flag) {
+			fromCmd, err := s.flags.GetString(p.flag)
+			if err != nil {
+				return nil, err
+			}
+			if fromCmd != "" {
+				termPrefix = fromCmd
+			}
+		}
+
+		if termPrefix == "" {
+			continue
+		}
+
+		nativeStrings = append(nativeStrings, &nativeStringsOptions{
+			Platform:   p.platform,
+			TermPrefix: termPrefix,
+			Dir:        filepath.Join(s.rootDir, p.dir),
+		})
+	}
+
+	return nativeStrings, nil
+}
+
+// SelectPlatformLocales returns platform files declaring the exported locales,
+// or nil if they shouldn't be updated. Their directories are relative to the project root.
+//
+// Defaults to nil.
+func (s *poeOptionsSelector) SelectPlatformLocales() (*platformLocalesOptions, error) {
+	sync := s.l10n.POEditorSyncPlatformLocales
+	// Only an explicitly passed flag overrides l10n.yaml, so that false disables the sync too.
+	if s.flagDefined(syncPlatformLocalesFlag) && s.flags.Changed(syncPlatformLocalesFlag) {
+		fromCmd, err := s.flags.GetBool(syncPlatformLocalesFlag)
+		if err != nil {
+			return nil, err
+		}
+		sync = fromCmd
+	}
+
+	if !sync {
+		return nil, nil
+	}
+
+	return &platformLocalesOptions{
+		IOSDir:        filepath.Join(s.rootDir, s.l10n.POEditorIOSDir),
+		AndroidResDir: filepath.Join(s.rootDir, s.l10n.POEditorAndroidResDir),
+	}, nil
+}
+
+// SelectPseudo returns the pseudo-locale generated from the template ARB file,
+// or nil if it shouldn't be generated.
+//
+// Defaults to nil. Padding defaults to pseudo.DefaultPadding.
+func (s *poeOptionsSelector) SelectPseudo() (*pseudoOptions, error) {
+	locale := s.l10n.POEditorPseudoLocale
+	if s.flagDefined(pseudoLocaleFlag) {
+		fromCmd, err := s.flags.GetString(pseudoLocaleFlag)
+		if err != nil {
+			return nil, err
+		}
+		if fromCmd != "" {
+			locale = fromCmd
+		}
+	}
+
+	if locale == "" {
+		return nil, nil
+	}
+
+	padding := pseudo.DefaultPadding
+	if s.l10n.POEditorPseudoPadding != nil {
+		padding = *s.l10n.POEditorPseudoPadding
+	}
+	// Zero disables the padding, so only an explicitly passed flag overrides it.
+	if s.flagDefined(pseudoPaddingFlag) && s.flags.Changed(pseudoPaddingFlag) {
+		fromCmd, err := s.flags.GetInt(pseudoPaddingFlag)
+		if err != nil {
+			return nil, err
+		}
+		padding = fromCmd
+	}
+
+	return newPseudoOptions(locale, padding)
+}
+
+// flagDefined reports whether the flag is defined for the command,
+// as not every command using the selector defines all of the flags.
+func (s *poeOptionsSelector) flagDefined(name string) bool {
+	return s.flags.Lookup(name) != nil
+}
@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +42,212 @@ func TestPrefixFromTemplateFileName(t *testing.T) {
 		})
 	}
 }
+
+func newTestSelector(t *testing.T, l10n *flutter.L10n, args ...string) *poeOptionsSelector {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int(concurrencyFlag, 0, "")
+	flags.String(placeholderMismatchFlag, "", "")
+	flags.String(incompletePluralsFlag, "", "")
+	flags.Float64(minCoverageFlag, 0, "")
+	flags.String(namingFlag, "", "")
+	flags.String(pseudoLocaleFlag, "", "")
+	flags.Int(pseudoPaddingFlag, 0, "")
+	flags.Bool(syncPlatformLocalesFlag, false, "")
+	assert.NoError(t, flags.Parse(args))
+
+	return &poeOptionsSelector{flags: flags, l10n: l10n}
+}
+
+func TestSelectFlagOrL10n(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Args     []string
+		L10n     *flutter.L10n
+		Select   func(*poeOptionsSelector) (any, error)
+		Expected any
+	}
+
+	selectConcurrency := func(s *poeOptionsSelector) (any, error) { return s.SelectConcurrency() }
+	selectPlaceholderMismatch := func(s *poeOptionsSelector) (any, error) { return s.SelectPlaceholderMismatch() }
+	selectIncompletePlurals := func(s *poeOptionsSelector) (any, error) { return s.SelectIncompletePlurals() }
+	selectMinCoverage := func(s *poeOptionsSelector) (any, error) { return s.SelectMinCoverage() }
+	selectNaming := func(s *poeOptionsSelector) (any, error) { return s.SelectNaming() }
+
+	testCases := []testCase{
+		{"concurrency default", nil, &flutter.L10n{}, selectConcurrency, 1},
+		{"concurrency from l10n.yaml", nil, &flutter.L10n{POEditorConcurrency: 4}, selectConcurrency, 4},
+		{
+			"concurrency flag overrides l10n.yaml",
+			[]string{"--concurrency", "8"}, &flutter.L10n{POEditorConcurrency: 4},
+			selectConcurrency, 8,
+		},
+
+		{"placeholder mismatch default", nil, &flutter.L10n{}, selectPlaceholderMismatch, poe2arb.PlaceholderMismatchWarn},
+		{
+			"placeholder mismatch from l10n.yaml",
+			nil, &flutter.L10n{POEditorPlaceholderMismatch: "skip"},
+			selectPlaceholderMismatch, poe2arb.PlaceholderMismatchSkip,
+		},
+		{
+			"placeholder mismatch flag overrides l10n.yaml",
+			[]string{"--placeholder-mismatch", "fail"}, &flutter.L10n{POEditorPlaceholderMismatch: "skip"},
+			selectPlaceholderMismatch, poe2arb.PlaceholderMismatchFail,
+		},
+
+		{"incomplete plurals default", nil, &flutter.L10n{}, selectIncompletePlurals, poe2arb.IncompletePluralWarn},
+		{
+			"incomplete plurals from l10n.yaml",
+			nil, &flutter.L10n{POEditorIncompletePlurals: "skip"},
+			selectIncompletePlurals, poe2arb.IncompletePluralSkip,
+		},
+		{
+			"incomplete plurals flag overrides l10n.yaml",
+			[]string{"--incomplete-plurals", "fail"}, &flutter.L10n{POEditorIncompletePlurals: "skip"},
+			selectIncompletePlurals, poe2arb.IncompletePluralFail,
+		},
+
+		{"min coverage default", nil, &flutter.L10n{}, selectMinCoverage, 0.0},
+		{"min coverage from l10n.yaml", nil, &flutter.L10n{POEditorMinCoverage: 80}, selectMinCoverage, 80.0},
+		{
+			"min coverage flag overrides l10n.yaml",
+			[]string{"--min-coverage", "95.5"}, &flutter.L10n{POEditorMinCoverage: 80},
+			selectMinCoverage, 95.5,
+		},
+		{
+			"min coverage zero flag overrides l10n.yaml",
+			[]string{"--min-coverage", "0"}, &flutter.L10n{POEditorMinCoverage: 80},
+			selectMinCoverage, 0.0,
+		},
+
+		{"naming default", nil, &flutter.L10n{}, selectNaming, poe2arb.NamingKeep},
+		{"naming from l10n.yaml", nil, &flutter.L10n{POEditorNaming: "dot-to-camel"}, selectNaming, poe2arb.NamingDotToCamel},
+		{
+			"naming flag overrides l10n.yaml",
+			[]string{"--naming", "snake-to-camel"}, &flutter.L10n{POEditorNaming: "dot-to-camel"},
+			selectNaming, poe2arb.NamingSnakeToCamel,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			value, err := testCase.Select(newTestSelector(t, testCase.L10n, testCase.Args...))
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, value)
+		})
+	}
+
+	t.Run("flag not defined", func(t *testing.T) {
+		sel := &poeOptionsSelector{
+			flags: pflag.NewFlagSet("test", pflag.ContinueOnError),
+			l10n:  &flutter.L10n{POEditorConcurrency: 2},
+		}
+
+		concurrency, err := sel.SelectConcurrency()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, concurrency)
+	})
+}
+
+func TestSelectFlavors(t *testing.T) {
+	sel := newTestSelector(t, &flutter.L10n{POEditorFlavors: map[string]*flutter.L10nFlavor{
+		"brandy":  {TermPrefixes: []string{"brandY"}, ARBDir: "lib/l10n_brandy"},
+		"brandx":  {TermPrefixes: []string{"brandX", "winter"}},
+		"invalid": nil,
+	}})
+
+	flavors := sel.SelectFlavors("lib/l10n")
+
+	assert.Equal(t, []*flavorOptions{
+		{Name: "brandx", TermPrefixes: []string{"brandX", "winter"}, OutputDir: filepath.Join("lib/l10n", "brandx")},
+		{Name: "brandy", TermPrefixes: []string{"brandY"}, OutputDir: "lib/l10n_brandy"},
+		{Name: "invalid", OutputDir: filepath.Join("lib/l10n", "invalid")},
+	}, flavors)
+
+	assert.Equal(t, []error{errors.New("flavor invalid has no term prefixes")}, validatePoeOptions(&poeOptions{
+		ProjectID:           "123",
+		Token:               "token",
+		Naming:              poe2arb.NamingKeep,
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		Flavors:             flavors,
+	}))
+}
+
+func TestSelectPlatformLocales(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Flags    []string
+		L10nSync bool
+		Expected bool
+	}
+
+	testCases := []testCase{
+		{"disabled by default", nil, false, false},
+		{"from l10n.yaml", nil, true, true},
+		{"flag enables", []string{"--sync-platform-locales"}, false, true},
+		{"flag disables", []string{"--sync-platform-locales=false"}, true, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			sel := newTestSelector(t, &flutter.L10n{
+				POEditorSyncPlatformLocales: testCase.L10nSync,
+				POEditorIOSDir:              "ios/Runner",
+				POEditorAndroidResDir:       "android/app/src/main/res",
+			}, testCase.Flags...)
+
+			platformLocales, err := sel.SelectPlatformLocales()
+
+			assert.NoError(t, err)
+			if testCase.Expected {
+				assert.Equal(t, &platformLocalesOptions{
+					IOSDir:        "ios/Runner",
+					AndroidResDir: "android/app/src/main/res",
+				}, platformLocales)
+			} else {
+				assert.Nil(t, platformLocales)
+			}
+		})
+	}
+}
+
+func TestSelectPseudo(t *testing.T) {
+	padding := func(p int) *int { return &p }
+
+	type testCase struct {
+		Name        string
+		Flags       []string
+		L10nLocale  string
+		L10nPadding *int
+		Expected    *pseudoOptions
+	}
+
+	testCases := []testCase{
+		{"disabled by default", nil, "", padding(10), nil},
+		{"from l10n.yaml", nil, "en_XA", padding(10), &pseudoOptions{flutter.Locale{Language: "en", Country: "XA"}, 10}},
+		{"default padding", nil, "en_XA", nil, &pseudoOptions{flutter.Locale{Language: "en", Country: "XA"}, 30}},
+		{
+			"flags override l10n.yaml",
+			[]string{"--pseudo-locale", "ar_XB", "--pseudo-padding", "0"},
+			"en_XA", padding(10),
+			&pseudoOptions{flutter.Locale{Language: "ar", Country: "XB"}, 0},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			sel := newTestSelector(t,
+				&flutter.L10n{POEditorPseudoLocale: testCase.L10nLocale, POEditorPseudoPadding: testCase.L10nPadding},
+				testCase.Flags...)
+
+			pseudo, err := sel.SelectPseudo()
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, pseudo)
+		})
+	}
+}
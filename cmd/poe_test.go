@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportFlavors(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}, {Name: "Polish", Code: "pl"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "appName", "definition": "Bank", "term_plural": ""},
+			{"term": "brandX:appName", "definition": "X Bank", "term_plural": ""},
+			{"term": "title", "definition": "Welcome", "term_plural": ""}
+		]`,
+		"pl.json": `[
+			{"term": "appName", "definition": "Bank", "term_plural": ""},
+			{"term": "brandX:appName", "definition": "", "term_plural": ""},
+			{"term": "title", "definition": "Witaj", "term_plural": ""}
+		]`,
+	})
+
+	outputDir := t.TempDir()
+	// left by an interrupted run
+	writeTestFiles(t, outputDir, map[string]string{stagingDirPrefix + "123/.backup/app_en.arb": "{}"})
+
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           outputDir,
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		FromSnapshot:        snapshotDir,
+		Flavors: []*flavorOptions{
+			{Name: "brandx", TermPrefixes: []string{"brandX"}, OutputDir: filepath.Join(outputDir, "brandx")},
+		},
+		Pseudo: &pseudoOptions{Locale: flutter.Locale{Language: "en", Country: "XA"}},
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		"app_en.arb":        "{\n    \"@@locale\": \"en\",\n    \"appName\": \"Bank\",\n    \"title\": \"Welcome\"\n}\n",
+		"app_pl.arb":        "{\n    \"@@locale\": \"pl\",\n    \"appName\": \"Bank\",\n    \"title\": \"Witaj\"\n}\n",
+		"brandx/app_en.arb": "{\n    \"@@locale\": \"en\",\n    \"appName\": \"X Bank\",\n    \"title\": \"Welcome\"\n}\n",
+		"brandx/app_pl.arb": "{\n    \"@@locale\": \"pl\",\n    \"appName\": \"Bank\",\n    \"title\": \"Witaj\"\n}\n",
+	}
+	for name, contents := range expected {
+		actual, err := os.ReadFile(filepath.Join(outputDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, contents, string(actual), name)
+	}
+
+	leftovers, err := filepath.Glob(filepath.Join(outputDir, stagingDirPrefix+"*"))
+	assert.NoError(t, err)
+	assert.Empty(t, leftovers)
+
+	// The pseudo-locale is generated only for the base ARB files.
+	assert.FileExists(t, filepath.Join(outputDir, "app_en_xa.arb"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "brandx", "app_en_xa.arb"))
+}
+
+func TestExportFlavorsAllOrNothing(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}, {Name: "Polish", Code: "pl"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "appName", "definition": "Bank", "term_plural": ""},
+			{"term": "brandX:appName", "definition": "X Bank", "term_plural": ""}
+		]`,
+		"pl.json": `[
+			{"term": "appName", "definition": "Bank", "term_plural": ""},
+			{"term": "brandX:appName", "definition": "{brand} Bank", "term_plural": ""}
+		]`,
+	})
+
+	outputDir := t.TempDir()
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           outputDir,
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchFail,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		FromSnapshot:        snapshotDir,
+		Flavors: []*flavorOptions{
+			{Name: "brandx", TermPrefixes: []string{"brandX"}, OutputDir: filepath.Join(outputDir, "brandx")},
+		},
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.Error(t, err)
+
+	// The base ARB files are valid, but not saved, as the flavor failed.
+	assert.NoFileExists(t, filepath.Join(outputDir, "app_en.arb"))
+	assert.NoFileExists(t, filepath.Join(outputDir, "app_pl.arb"))
+}
+
+func TestExportPseudoLocale(t *testing.T) {
+	snapshotDir := t.TempDir()
+	metadata, err := json.Marshal(snapshotMetadata{
+		ProjectID: "123",
+		Languages: []snapshotLanguage{{Name: "English", Code: "en"}},
+	})
+	assert.NoError(t, err)
+	writeTestFiles(t, snapshotDir, map[string]string{
+		snapshotMetadataFile: string(metadata),
+		"en.json": `[
+			{"term": "greeting", "definition": "Hi {name}", "term_plural": ""},
+			{"term": "items", "definition": {"one": "{count} item", "other": "{count} items"}, "term_plural": "count"}
+		]`,
+	})
+
+	outputDir := t.TempDir()
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           outputDir,
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		FromSnapshot:        snapshotDir,
+		Pseudo:              &pseudoOptions{Locale: flutter.Locale{Language: "en", Country: "XA"}, Padding: 0},
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.NoError(t, err)
+
+	actual, err := os.ReadFile(filepath.Join(outputDir, "app_en_xa.arb"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n    \"@@locale\": \"en_XA\",\n    \"greeting\": \"[Ĥî {name}]\",\n"+
+		"    \"items\": \"{count, plural, one {[{count} îţéɱ]} other {[{count} îţéɱš]}}\"\n}\n", string(actual))
+
+	tags, err := c.exportedLanguageTags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"en"}, tags)
+}
+
+func TestExportFromSourceDir(t *testing.T) {
+	sourceDir := t.TempDir()
+	writeTestFiles(t, sourceDir, map[string]string{
+		"app_en.arb": `{"@@locale": "en", "greeting": "Hello, {name}!", "@greeting": {"placeholders": {"name": {}}}}`,
+		"app_pl.arb": `{"@@locale": "pl", "greeting": "Cześć, {name}!"}`,
+	})
+
+	options := &poeOptions{
+		TermPrefix:          "app",
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           t.TempDir(),
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		SourceDir:           sourceDir,
+	}
+	c, err := NewPoeCommand(options, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	err = c.Export(context.Background())
+	assert.NoError(t, err)
+
+	expected := map[string]string{
+		"app_en.arb": "{\n    \"@@locale\": \"en\",\n    \"greeting\": \"Hello, {name}!\",\n" +
+			"    \"@greeting\": {\n        \"placeholders\": {\n            \"name\": {\n                \"type\": \"String\"\n" +
+			"            }\n        }\n    }\n}\n",
+		"app_pl.arb": "{\n    \"@@locale\": \"pl\",\n    \"greeting\": \"Cześć, {name}!\"\n}\n",
+	}
+	for name, contents := range expected {
+		actual, err := os.ReadFile(filepath.Join(options.OutputDir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, contents, string(actual), name)
+	}
+
+	options.FromSnapshot = t.TempDir()
+	_, err = NewPoeCommand(options, log.New(new(bytes.Buffer)))
+	assert.ErrorContains(t, err, "snapshot and source directory can't be used together")
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/leancodepl/poe2arb/convert/pseudo"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/spf13/cobra"
+)
+
+var pseudoCmd = &cobra.Command{
+	Use: "pseudo [template ARB file]",
+	Short: "Generates a pseudo-locale ARB file from the template ARB file. " +
+		"Without the file, must be run from the Flutter project root directory or its subdirectory.",
+	Args:          cobra.MaximumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runPseudo,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// An explicitly given template file doesn't need a Flutter project.
+		if len(args) > 0 {
+			return nil
+		}
+
+		return versionGuard.GetFlutterConfigAndEnsureSufficientVersion(cmd, args)
+	},
+}
+
+const (
+	pseudoLocaleFlag  = "pseudo-locale"
+	pseudoPaddingFlag = "pseudo-padding"
+	localeFlag        = "locale"
+	paddingFlag       = "padding"
+)
+
+func init() {
+	pseudoCmd.Flags().String(localeFlag, "", fmt.Sprintf("Pseudo-locale of the generated ARB file [default: %s]", pseudo.DefaultLocale))
+	pseudoCmd.Flags().Int(paddingFlag, 0, fmt.Sprintf("Percentage messages are lengthened by [default: %d]", pseudo.DefaultPadding))
+	pseudoCmd.Flags().Bool(useEscapingFlag, false, "Whether apostrophes quote literal text, as with gen-l10n use-escaping option")
+}
+
+// pseudoOptions describes the pseudo-locale generated from the template ARB file.
+type pseudoOptions struct {
+	Locale flutter.Locale
+	// Padding is the percentage messages are lengthened by.
+	Padding int
+}
+
+func newPseudoOptions(locale string, padding int) (*pseudoOptions, error) {
+	flutterLocale, err := flutter.ParseLocale(locale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pseudo-locale: %w", err)
+	}
+
+	if padding < 0 {
+		return nil, errors.New("pseudo-locale padding must not be negative")
+	}
+
+	return &pseudoOptions{Locale: flutterLocale, Padding: padding}, nil
+}
+
+func runPseudo(cmd *cobra.Command, args []string) error {
+	log := getLogger(cmd)
+
+	logSub := log.Info("loading options").Sub()
+
+	l10n := &flutter.L10n{}
+	var templatePath string
+	if len(args) > 0 {
+		templatePath = args[0]
+	} else {
+		flutterCfg := flutterConfigFromCommand(cmd)
+		l10n = flutterCfg.L10n
+		templatePath = filepath.Join(flutterCfg.RootDir, l10n.ARBDir, l10n.TemplateArbFile)
+	}
+
+	prefix, err := prefixFromTemplateFileName(filepath.Base(templatePath))
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	locale := l10n.POEditorPseudoLocale
+	if fromCmd, _ := cmd.Flags().GetString(localeFlag); fromCmd != "" {
+		locale = fromCmd
+	} else if locale == "" {
+		locale = pseudo.DefaultLocale
+	}
+
+	padding := pseudo.DefaultPadding
+	if l10n.POEditorPseudoPadding != nil {
+		padding = *l10n.POEditorPseudoPadding
+	}
+	if cmd.Flags().Changed(paddingFlag) {
+		padding, _ = cmd.Flags().GetInt(paddingFlag)
+	}
+
+	options, err := newPseudoOptions(locale, padding)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	useEscaping, _ := cmd.Flags().GetBool(useEscapingFlag)
+	useEscaping = useEscaping || l10n.UseEscaping
+
+	outputPath := filepath.Join(filepath.Dir(templatePath), fmt.Sprintf("%s%s.arb", prefix, options.Locale.StringFilename()))
+
+	logSub = log.Info("generating %s pseudo-locale from %s", options.Locale, templatePath).Sub()
+	if err := writePseudoARB(logSub, templatePath, outputPath, options, useEscaping); err != nil {
+		return err
+	}
+
+	log.Success("done")
+
+	return nil
+}
+
+// GeneratePseudoLocale generates the pseudo-locale ARB file from the exported template ARB file.
+func (c *poeCommand) GeneratePseudoLocale() error {
+	if c.options.Pseudo == nil {
+		return nil
+	}
+
+	templatePath := filepath.Join(c.options.OutputDir, c.arbFileName(c.options.TemplateLocale))
+	outputPath := filepath.Join(c.options.OutputDir, c.arbFileName(c.options.Pseudo.Locale))
+
+	logSub := c.log.Info("generating %s pseudo-locale", c.options.Pseudo.Locale).Sub()
+
+	return writePseudoARB(logSub, templatePath, outputPath, c.options.Pseudo, c.options.UseEscaping)
+}
+
+// isPseudoFile reports whether the ARB file is the generated pseudo-locale,
+// which is not exported from POEditor.
+func (c *poeCommand) isPseudoFile(fileName string) bool {
+	return c.options.Pseudo != nil && fileName == c.arbFileName(c.options.Pseudo.Locale)
+}
+
+// writePseudoARB converts the template ARB file to the pseudo-locale one.
+// An unchanged file is left untouched.
+func writePseudoARB(log *log.Logger, templatePath, outputPath string, options *pseudoOptions, useEscaping bool) error {
+	template, err := os.Open(templatePath)
+	if err != nil {
+		log.Error("failed: " + err.Error())
+		return err
+	}
+	defer template.Close()
+
+	var b bytes.Buffer
+	conv := pseudo.NewConverter(template, &pseudo.ConverterOptions{
+		Locale:      options.Locale,
+		Padding:     options.Padding,
+		UseEscaping: useEscaping,
+	})
+	if err := conv.Convert(&b); err != nil {
+		log.Error("failed: " + err.Error())
+		return err
+	}
+
+	if current, err := os.ReadFile(outputPath); err == nil && bytes.Equal(current, b.Bytes()) {
+		log.Info("%s: up to date", filepath.Base(outputPath))
+		return nil
+	}
+
+	if err := os.WriteFile(outputPath, b.Bytes(), 0o666); err != nil {
+		log.Error("failed: " + err.Error())
+		return err
+	}
+
+	log.Success("saved %s", outputPath)
+
+	return nil
+}
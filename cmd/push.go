@@ -0,0 +1,520 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/arb2poe"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/leancodepl/poe2arb/source"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pushCmd = &cobra.Command{
+		Use: "push",
+		Short: "Uploads new and changed terms from the template ARB to POEditor. " +
+			"Must be run from the Flutter project root directory or its subdirectory.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          runPush,
+		PreRunE:       versionGuard.GetFlutterConfigAndEnsureSufficientVersion,
+	}
+	termWithPrefixRegexp = regexp.MustCompile("^([a-zA-Z]+):")
+)
+
+const (
+	dryRunFlag   = "dry-run"
+	obsoleteFlag = "obsolete"
+
+	obsoleteKeep   = "keep"
+	obsoleteTag    = "tag"
+	obsoleteDelete = "delete"
+
+	// obsoleteTermTag is the POEditor tag added to terms no longer present in the template ARB.
+	obsoleteTermTag = "obsolete"
+
+	// defaultPlaceholderType and defaultCountPlaceholderType are the types
+	// of POEditor placeholders defined without one.
+	defaultPlaceholderType      = "String"
+	defaultCountPlaceholderType = "num"
+)
+
+func init() {
+	pushCmd.Flags().StringP(projectIDFlag, "p", "", "POEditor project ID")
+	pushCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
+	pushCmd.Flags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
+	pushCmd.Flags().StringP(outputDirFlag, "o", "", `ARB files directory [default: "."]`)
+	pushCmd.Flags().String(sourceDirFlag, "", "Upload to a directory of POEditor JSON files, instead of POEditor")
+	pushCmd.Flags().Bool(dryRunFlag, false, "Only print the changes, without uploading them")
+	pushCmd.Flags().String(obsoleteFlag, obsoleteKeep,
+		fmt.Sprintf("What to do with POEditor terms missing in the template ARB: %s, %s (with %q tag) or %s",
+			obsoleteKeep, obsoleteTag, obsoleteTermTag, obsoleteDelete))
+}
+
+func runPush(cmd *cobra.Command, args []string) error {
+	log := getLogger(cmd)
+
+	logSub := log.Info("loading options").Sub()
+
+	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	obsolete, _ := cmd.Flags().GetString(obsoleteFlag)
+	if !slices.Contains([]string{obsoleteKeep, obsoleteTag, obsoleteDelete}, obsolete) {
+		err := fmt.Errorf("invalid --%s value %q, must be one of: %s, %s, %s",
+			obsoleteFlag, obsolete, obsoleteKeep, obsoleteTag, obsoleteDelete)
+		logSub.Error(err.Error())
+		return err
+	}
+
+	sel, err := getOptionsSelector(cmd)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	options, err := sel.SelectOptions()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	poeCmd, err := NewPoeCommand(options, log)
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	templatePath := filepath.Join(options.OutputDir, poeCmd.arbFileName(options.TemplateLocale))
+	logSub = log.Info("reading template ARB %s", templatePath).Sub()
+	localTerms, err := readTemplateTerms(templatePath, options)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	log.Info("fetching project languages")
+	langs, err := poeCmd.source.Languages()
+	if err != nil {
+		log.Error("failed fetching languages: " + err.Error())
+		return err
+	}
+
+	var remoteTerms []*convert.POETerm
+	if lang, ok := findLanguage(langs, options.TemplateLocale); ok {
+		logSub = log.Info("fetching JSON export for %s (%s)", lang.Name, lang.Code).Sub()
+		remoteTerms, err = poeCmd.fetchTerms(cmd.Context(), logSub, lang.Code)
+		if err != nil {
+			return err
+		}
+	} else {
+		logSub := log.Info("adding language %s to project", options.TemplateLocale).Sub()
+		if dryRun {
+			logSub.Info("skipped, dry run")
+		} else if err := poeCmd.source.AddLanguage(options.TemplateLocale.StringHyphen()); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	plan := planPush(localTerms, remoteTerms, options.TermPrefix, options.syntax())
+	plan.Report(log, obsolete)
+
+	if dryRun {
+		log.Success("dry run, nothing was changed")
+		return nil
+	}
+
+	if err := poeCmd.applyPushPlan(plan, obsolete); err != nil {
+		return err
+	}
+
+	log.Success("done")
+
+	return nil
+}
+
+func readTemplateTerms(templatePath string, options *poeOptions) ([]*convert.POETerm, error) {
+	file, err := os.Open(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	converter := arb2poe.NewConverter(file, options.TemplateLocale, options.TermPrefix, options.syntax())
+	lang, terms, err := converter.ConvertTerms()
+	if err != nil {
+		if errors.Is(err, arb2poe.ErrNoTerms) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if lang != options.TemplateLocale {
+		return nil, fmt.Errorf("template ARB has locale %s, expected %s", lang, options.TemplateLocale)
+	}
+
+	return terms, nil
+}
+
+func findLanguage(langs []poeditor.Language, locale flutter.Locale) (poeditor.Language, bool) {
+	for _, lang := range langs {
+		if flutterLocale, err := flutter.ParseLocale(lang.Code); err == nil && flutterLocale == locale {
+			return lang, true
+		}
+	}
+
+	return poeditor.Language{}, false
+}
+
+func (c *poeCommand) fetchTerms(ctx context.Context, log *log.Logger, langCode string) ([]*convert.POETerm, error) {
+	export, err := c.FetchExport(ctx, log, langCode)
+	if err != nil {
+		return nil, err
+	}
+	defer export.Close()
+
+	var terms []*convert.POETerm
+	if err := json.NewDecoder(export).Decode(&terms); err != nil {
+		log.Error("decoding JSON failed: " + err.Error())
+		return nil, fmt.Errorf("decoding json failed: %w", err)
+	}
+
+	return terms, nil
+}
+
+func (c *poeCommand) applyPushPlan(plan *pushPlan, obsolete string) error {
+	upload := append(slices.Clone(plan.Added), plan.Changed...)
+
+	// POEditor doesn't update comments of existing terms on upload, so they're updated separately.
+	// Other sources overwrite them with the uploaded terms.
+	editor, canEditTerms := c.source.(source.TermsEditor)
+	if !canEditTerms {
+		for _, term := range plan.Commented {
+			if !slices.Contains(plan.Changed, term) {
+				upload = append(upload, term)
+			}
+		}
+	}
+
+	if len(upload) > 0 {
+		logSub := c.log.Info("uploading %d terms to POEditor", len(upload)).Sub()
+
+		var b bytes.Buffer
+		if err := json.NewEncoder(&b).Encode(upload); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+
+		err := c.source.Upload(c.options.TemplateLocale.StringHyphen(), &b, true)
+		if err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	if canEditTerms && len(plan.Commented) > 0 {
+		logSub := c.log.Info("updating comments of %d terms", len(plan.Commented)).Sub()
+
+		var commented []poeditor.Term
+		for _, term := range plan.Commented {
+			commented = append(commented, poeditor.Term{Term: term.Term, Context: term.Context, Comment: term.Comment})
+		}
+
+		if err := editor.UpdateTermsComments(commented); err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	if len(plan.Removed) == 0 {
+		return nil
+	}
+
+	switch obsolete {
+	case obsoleteTag:
+		logSub := c.log.Info("tagging %d obsolete terms with %q", len(plan.Removed), obsoleteTermTag).Sub()
+		return c.tagTerms(logSub, plan.IsRemoved, obsoleteTermTag)
+
+	case obsoleteDelete:
+		logSub := c.log.Info("deleting %d obsolete terms", len(plan.Removed)).Sub()
+		return c.deleteTerms(logSub, plan.Removed)
+	}
+
+	return nil
+}
+
+// tagTerms adds the tag to the POEditor terms matched by the function, keeping their other tags.
+func (c *poeCommand) tagTerms(log *log.Logger, match func(term, termContext string) bool, tag string) error {
+	editor, err := c.termsEditor()
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	terms, err := editor.Terms()
+	if err != nil {
+		log.Error("fetching terms failed: " + err.Error())
+		return err
+	}
+
+	var tagged []poeditor.Term
+	for _, term := range terms {
+		if !match(term.Term, term.Context) || slices.Contains(term.Tags, tag) {
+			continue
+		}
+
+		term.Tags = append(term.Tags, tag)
+		tagged = append(tagged, term)
+	}
+
+	if len(tagged) > 0 {
+		if err := editor.UpdateTermsTags(tagged); err != nil {
+			log.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteTerms deletes the given POEditor terms.
+func (c *poeCommand) deleteTerms(log *log.Logger, terms []*convert.POETerm) error {
+	editor, err := c.termsEditor()
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	var deleted []poeditor.Term
+	for _, term := range terms {
+		deleted = append(deleted, poeditor.Term{Term: term.Term, Context: term.Context})
+	}
+
+	if err := editor.DeleteTerms(deleted); err != nil {
+		log.Error("failed: " + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// termsEditor returns the source as source.TermsEditor, failing if its terms can't be edited.
+func (c *poeCommand) termsEditor() (source.TermsEditor, error) {
+	editor, ok := c.source.(source.TermsEditor)
+	if !ok {
+		return nil, errors.New("editing terms is not supported by the translation source")
+	}
+
+	return editor, nil
+}
+
+// pushPlan describes the changes needed to bring POEditor terms in sync with the template ARB.
+type pushPlan struct {
+	// Added are terms present in the template ARB, but not in POEditor.
+	Added []*convert.POETerm
+	// Changed are terms whose template translation differs from POEditor.
+	Changed []*convert.POETerm
+	// Commented are terms whose comment differs from POEditor.
+	Commented []*convert.POETerm
+	// Removed are POEditor terms with the same prefix that are not present in the template ARB.
+	Removed []*convert.POETerm
+}
+
+// planPush compares local terms converted from the template ARB with the terms
+// exported from POEditor. Only remote terms with the given prefix are considered.
+// Terms are identified by their name and context, like in POEditor.
+func planPush(local, remote []*convert.POETerm, termPrefix string, options icu.Options) *pushPlan {
+	plan := &pushPlan{}
+
+	remoteByKey := map[termKey]*convert.POETerm{}
+	for _, term := range remote {
+		if termPrefixOf(term.Term) == termPrefix {
+			remoteByKey[keyOf(term)] = term
+		}
+	}
+
+	localKeys := map[termKey]bool{}
+	for _, term := range local {
+		localKeys[keyOf(term)] = true
+
+		remoteTerm, ok := remoteByKey[keyOf(term)]
+		if !ok {
+			plan.Added = append(plan.Added, term)
+			continue
+		}
+
+		if !sameDefinitions(term, remoteTerm, options) {
+			plan.Changed = append(plan.Changed, term)
+		}
+		if term.Comment != remoteTerm.Comment {
+			plan.Commented = append(plan.Commented, term)
+		}
+	}
+
+	for _, term := range remote {
+		if _, ok := remoteByKey[keyOf(term)]; ok && !localKeys[keyOf(term)] {
+			plan.Removed = append(plan.Removed, term)
+		}
+	}
+
+	return plan
+}
+
+type termKey struct {
+	term    string
+	context string
+}
+
+func keyOf(term *convert.POETerm) termKey {
+	return termKey{term.Term, term.Context}
+}
+
+// sameDefinitions reports whether definitions of both terms have the same meaning. Placeholder
+// definitions are compared per placeholder, as a placeholder can be defined at any of its
+// occurrences, and placeholders without a type have the default one.
+// E.g. "{name,String}" is the same as "{name}", as written by hand in POEditor.
+func sameDefinitions(a, b *convert.POETerm, options icu.Options) bool {
+	normalizedA, errA := normalizeDefinition(a, options)
+	normalizedB, errB := normalizeDefinition(b, options)
+	if errA != nil || errB != nil {
+		return a.Definition.Equal(b.Definition)
+	}
+
+	return normalizedA.Equal(normalizedB)
+}
+
+// normalizeDefinition defines every placeholder occurrence with the placeholder's type and format.
+// Count placeholders default to num, like in Flutter, and other placeholders to String.
+func normalizeDefinition(term *convert.POETerm, options icu.Options) (convert.POETermDefinition, error) {
+	d := term.Definition
+	messages := map[string]icu.Message{}
+	definitions := map[string]icu.Argument{}
+	countNames := map[string]bool{}
+	if d.IsPlural {
+		countNames[term.CountPlaceholderName()] = true
+	}
+
+	_, err := mapDefinition(d, func(translation string) (string, error) {
+		m, err := icu.Parse(translation, options)
+		if err != nil {
+			return "", err
+		}
+
+		messages[translation] = m
+		icu.Walk(m, func(node icu.Node) {
+			switch n := node.(type) {
+			case *icu.Argument:
+				if _, defined := definitions[n.Name]; n.Type != "" && !defined {
+					definitions[n.Name] = *n
+				}
+			case *icu.Plural:
+				countNames[n.Name] = true
+			}
+		})
+
+		return translation, nil
+	})
+	if err != nil {
+		return convert.POETermDefinition{}, err
+	}
+
+	return mapDefinition(d, func(translation string) (string, error) {
+		m := messages[translation]
+		icu.Walk(m, func(node icu.Node) {
+			if n, ok := node.(*icu.Argument); ok {
+				n.Type, n.Style = defaultPlaceholderType, ""
+				if countNames[n.Name] {
+					n.Type = defaultCountPlaceholderType
+				}
+				if definition, ok := definitions[n.Name]; ok {
+					n.Type, n.Style = definition.Type, definition.Style
+				}
+			}
+		})
+
+		return icu.Print(m, options), nil
+	})
+}
+
+func mapDefinition(
+	d convert.POETermDefinition,
+	mapper func(string) (string, error),
+) (convert.POETermDefinition, error) {
+	if d.IsPlural {
+		if d.Plural == nil {
+			return d, nil
+		}
+
+		plural, err := d.Plural.Map(mapper)
+		if err != nil {
+			return convert.POETermDefinition{}, err
+		}
+		return convert.POETermDefinition{IsPlural: true, Plural: plural}, nil
+	}
+
+	if d.Value == nil {
+		return d, nil
+	}
+
+	value, err := mapper(*d.Value)
+	if err != nil {
+		return convert.POETermDefinition{}, err
+	}
+	return convert.POETermDefinition{Value: &value}, nil
+}
+
+func termPrefixOf(term string) string {
+	if matches := termWithPrefixRegexp.FindStringSubmatch(term); matches != nil {
+		return matches[1]
+	}
+
+	return ""
+}
+
+func (p *pushPlan) IsRemoved(term, termContext string) bool {
+	return slices.ContainsFunc(p.Removed, func(t *convert.POETerm) bool {
+		return t.Term == term && t.Context == termContext
+	})
+}
+
+func (p *pushPlan) Report(log *log.Logger, obsolete string) {
+	if len(p.Added) == 0 && len(p.Changed) == 0 && len(p.Commented) == 0 && len(p.Removed) == 0 {
+		log.Success("POEditor terms are up to date")
+		return
+	}
+
+	report := func(msg string, terms []*convert.POETerm) {
+		if len(terms) == 0 {
+			return
+		}
+
+		logSub := log.Info(msg, len(terms)).Sub()
+		for _, term := range terms {
+			logSub.Info(term.Term)
+		}
+	}
+
+	report("%d new terms to upload", p.Added)
+	report("%d changed terms to upload", p.Changed)
+	report("%d terms with changed comments to update", p.Commented)
+
+	switch obsolete {
+	case obsoleteTag:
+		report("%d obsolete terms to tag", p.Removed)
+	case obsoleteDelete:
+		report("%d obsolete terms to delete", p.Removed)
+	default:
+		report("%d obsolete terms to keep", p.Removed)
+	}
+}
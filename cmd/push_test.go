@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanPush(t *testing.T) {
+	options := icu.Options{Relaxed: true}
+
+	term := func(name, value string) *convert.POETerm {
+		return &convert.POETerm{Term: name, Definition: convert.POETermDefinition{Value: &value}}
+	}
+
+	termNames := func(terms []*convert.POETerm) []string {
+		var names []string
+		for _, term := range terms {
+			names = append(names, term.Term)
+		}
+		return names
+	}
+
+	t.Run("no prefix", func(t *testing.T) {
+		local := []*convert.POETerm{
+			term("same", "Same"),
+			term("changed", "Changed locally"),
+			term("added", "Added"),
+		}
+		remote := []*convert.POETerm{
+			term("same", "Same"),
+			term("changed", "Changed"),
+			term("removed", "Removed"),
+			term("other:removed", "Other package term"),
+		}
+
+		plan := planPush(local, remote, "", options)
+
+		assert.Equal(t, []string{"added"}, termNames(plan.Added))
+		assert.Equal(t, []string{"changed"}, termNames(plan.Changed))
+		assert.Equal(t, []string{"removed"}, termNames(plan.Removed))
+		assert.True(t, plan.IsRemoved("removed", ""))
+		assert.False(t, plan.IsRemoved("other:removed", ""))
+	})
+
+	t.Run("with prefix", func(t *testing.T) {
+		local := []*convert.POETerm{
+			term("prefix:same", "Same"),
+			term("prefix:added", "Added"),
+		}
+		remote := []*convert.POETerm{
+			term("prefix:same", "Same"),
+			term("prefix:removed", "Removed"),
+			term("removed", "Unprefixed term"),
+		}
+
+		plan := planPush(local, remote, "prefix", options)
+
+		assert.Equal(t, []string{"prefix:added"}, termNames(plan.Added))
+		assert.Empty(t, plan.Changed)
+		assert.Equal(t, []string{"prefix:removed"}, termNames(plan.Removed))
+	})
+
+	t.Run("comment changed", func(t *testing.T) {
+		commented := func(name, value, comment string) *convert.POETerm {
+			term := term(name, value)
+			term.Comment = comment
+			return term
+		}
+
+		local := []*convert.POETerm{
+			commented("described", "Same", "New description"),
+			commented("both", "Changed locally", "New description"),
+		}
+		remote := []*convert.POETerm{
+			commented("described", "Same", "Old description"),
+			commented("both", "Changed", "Old description"),
+		}
+
+		plan := planPush(local, remote, "", options)
+
+		assert.Empty(t, plan.Added)
+		assert.Equal(t, []string{"both"}, termNames(plan.Changed))
+		assert.Equal(t, []string{"described", "both"}, termNames(plan.Commented))
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		local := []*convert.POETerm{term("same", "Same")}
+		remote := []*convert.POETerm{term("same", "Same")}
+
+		plan := planPush(local, remote, "", options)
+
+		assert.Empty(t, plan.Added)
+		assert.Empty(t, plan.Changed)
+		assert.Empty(t, plan.Commented)
+		assert.Empty(t, plan.Removed)
+	})
+
+	t.Run("same placeholders written differently", func(t *testing.T) {
+		plural := func(name, one, other string) *convert.POETerm {
+			return &convert.POETerm{
+				Term:       name,
+				TermPlural: ".",
+				Definition: convert.POETermDefinition{
+					IsPlural: true,
+					Plural:   &convert.POETermPluralDefinition{One: &one, Other: other},
+				},
+			}
+		}
+
+		local := []*convert.POETerm{
+			term("default", "Hi {name,String}"),
+			term("defined", "{name,String} and {name}"),
+			plural("count", "{count,num} item by {name,String}", "{count} items by {name}"),
+			term("typeChanged", "{amount,int}"),
+		}
+		remote := []*convert.POETerm{
+			term("default", "Hi {name}"),
+			term("defined", "{name} and {name,String}"),
+			plural("count", "{count} item by {name}", "{count} items by {name}"),
+			term("typeChanged", "{amount,double}"),
+		}
+
+		plan := planPush(local, remote, "", options)
+
+		assert.Equal(t, []string{"typeChanged"}, termNames(plan.Changed))
+	})
+
+	t.Run("terms with contexts", func(t *testing.T) {
+		withContext := func(name, value, termContext string) *convert.POETerm {
+			term := term(name, value)
+			term.Context = termContext
+			return term
+		}
+
+		local := []*convert.POETerm{
+			term("shared", "Changed locally"),
+		}
+		remote := []*convert.POETerm{
+			term("shared", "Shared"),
+			withContext("shared", "Changed locally", "other"),
+		}
+
+		plan := planPush(local, remote, "", options)
+
+		assert.Empty(t, plan.Added)
+		assert.Equal(t, []string{"shared"}, termNames(plan.Changed))
+		assert.True(t, plan.IsRemoved("shared", "other"))
+		assert.False(t, plan.IsRemoved("shared", ""))
+	})
+}
+
+func TestPushExactPluralMatch(t *testing.T) {
+	// Older poe2arb versions wrote POEditor's one form as =1 in every locale.
+	templatePath := filepath.Join(t.TempDir(), "app_en.arb")
+	arb := `{
+    "@@locale": "en",
+    "apples": "{count, plural, =1 {One apple} other {{count} apples}}"
+}`
+	assert.NoError(t, os.WriteFile(templatePath, []byte(arb), 0o666))
+
+	options := &poeOptions{TemplateLocale: flutter.Locale{Language: "en"}}
+	local, err := readTemplateTerms(templatePath, options)
+	assert.NoError(t, err)
+
+	assert.Len(t, local, 1)
+	assert.True(t, local[0].Definition.IsPlural)
+	assert.Equal(t, "One apple", *local[0].Definition.Plural.One)
+	assert.Equal(t, "{count} apples", local[0].Definition.Plural.Other)
+
+	one := "One apple"
+	remote := []*convert.POETerm{{
+		Term:       "apples",
+		TermPlural: ".",
+		Definition: convert.POETermDefinition{
+			IsPlural: true,
+			Plural:   &convert.POETermPluralDefinition{One: &one, Other: "{count} apples"},
+		},
+	}}
+
+	plan := planPush(local, remote, "", icu.Options{Relaxed: true})
+
+	assert.Empty(t, plan.Added)
+	assert.Empty(t, plan.Changed)
+}
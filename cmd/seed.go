@@ -27,6 +27,7 @@ func init() {
 	seedCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
 	seedCmd.Flags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
 	seedCmd.Flags().StringP(outputDirFlag, "o", "", `Output directory [default: "."]`)
+	seedCmd.Flags().String(sourceDirFlag, "", "Seed a directory of POEditor JSON files, instead of POEditor")
 	seedCmd.Flags().StringSliceP(overrideLangsFlag, "", []string{}, "Override downloaded languages")
 }
 
@@ -75,14 +76,21 @@ func runSeed(cmd *cobra.Command, args []string) error {
 		fileLog.Info("found %d ARB files", len(files))
 	}
 
-	poeClient := poeditor.NewClient(options.Token)
+	poeCmd, err := NewPoeCommand(options, log)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
 
-	availableLangs, err := poeClient.GetProjectLanguages(options.ProjectID)
+	availableLangs, err := poeCmd.source.Languages()
 	if err != nil {
 		log.Error("failed fetching languages: " + err.Error())
 		return err
 	}
 
+	// Only POEditor limits the upload rate.
+	_, rateLimited := poeCmd.source.(*poeditor.Project)
+
 	first := true
 	freeAccountRateLimit := false
 	for _, filePath := range files {
@@ -95,7 +103,7 @@ func runSeed(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		converter := arb2poe.NewConverter(file, options.TemplateLocale, options.TermPrefix)
+		converter := arb2poe.NewConverter(file, options.TemplateLocale, options.TermPrefix, options.syntax())
 
 		var b bytes.Buffer
 		flutterLocale, err := converter.Convert(&b)
@@ -136,14 +144,14 @@ func runSeed(cmd *cobra.Command, args []string) error {
 		if !availableLangFound {
 			langLog := fileLog.Info("adding language %s to project", flutterLocale).Sub()
 
-			err = poeClient.AddLanguage(options.ProjectID, lang)
+			err = poeCmd.source.AddLanguage(lang)
 			if err != nil {
 				langLog.Error("failed: " + err.Error())
 				return err
 			}
 		}
 
-		if !first {
+		if !first && rateLimited {
 			rateLimitTimeout := poeditor.PaidAccountUploadRateLimit
 			rateLimitName := "(paid account)"
 			if freeAccountRateLimit {
@@ -159,7 +167,7 @@ func runSeed(cmd *cobra.Command, args []string) error {
 
 		uploadFileReader := bytes.NewReader(b.Bytes())
 		for {
-			err = poeClient.Upload(options.ProjectID, lang, uploadFileReader)
+			err = poeCmd.source.Upload(lang, uploadFileReader, false)
 
 			if err != nil {
 				var poeErr *poeditor.Error
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/leancodepl/poe2arb/source"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use: "snapshot <dir>",
+	Short: "Saves raw POEditor JSON exports of all languages to a directory, " +
+		"to convert them later with poe --from-snapshot. " +
+		"Must be run from the Flutter project root directory or its subdirectory.",
+	Args:          cobra.ExactArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runSnapshot,
+	PreRunE:       versionGuard.GetFlutterConfigAndEnsureSufficientVersion,
+}
+
+// snapshotMetadataFile is the name of the file describing the snapshot,
+// next to the language exports named <language code>.json.
+const snapshotMetadataFile = "snapshot.json"
+
+func init() {
+	snapshotCmd.Flags().StringP(projectIDFlag, "p", "", "POEditor project ID")
+	snapshotCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
+	snapshotCmd.Flags().StringSliceP(overrideLangsFlag, "", []string{}, "Override saved languages")
+	snapshotCmd.Flags().IntP(concurrencyFlag, "", 0, "Number of languages downloaded at the same time [default: 1]")
+}
+
+func runSnapshot(cmd *cobra.Command, args []string) error {
+	log := getLogger(cmd)
+	dir := args[0]
+
+	logSub := log.Info("loading options").Sub()
+
+	sel, err := getOptionsSelector(cmd)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	options, err := sel.SelectOptions()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	poeCmd, err := NewPoeCommand(options, log)
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	logSub = log.Info("fetching project languages").Sub()
+	langs, err := poeCmd.GetExportLanguages()
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		log.Error("creating directory %s failed: %s", dir, err)
+		return err
+	}
+
+	if err := poeCmd.SaveSnapshot(cmd.Context(), dir, langs); err != nil {
+		return err
+	}
+
+	log.Success("saved snapshot of %d languages to %s", len(langs), dir)
+
+	return nil
+}
+
+// snapshotMetadata describes the project and its languages at the time the snapshot was taken.
+type snapshotMetadata struct {
+	ProjectID      string             `json:"projectId"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	Poe2ArbVersion string             `json:"poe2arbVersion,omitempty"`
+	Languages      []snapshotLanguage `json:"languages"`
+}
+
+type snapshotLanguage struct {
+	Name         string    `json:"name"`
+	Code         string    `json:"code"`
+	Percentage   float64   `json:"percentage"`
+	Translations int       `json:"translations"`
+	Updated      time.Time `json:"updated"`
+}
+
+// snapshot is a directory with raw POEditor exports saved by the snapshot command.
+// The exports are read like any other source directory, but the languages come from
+// the metadata, keeping their names and update times from POEditor.
+type snapshot struct {
+	*source.Dir
+	metadata *snapshotMetadata
+}
+
+func openSnapshot(dir string) (*snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotMetadataFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	var metadata snapshotMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("decoding snapshot metadata: %w", err)
+	}
+
+	return &snapshot{
+		Dir:      source.NewDir(dir, &source.DirOptions{ReadOnly: true, Ignored: []string{snapshotMetadataFile}}),
+		metadata: &metadata,
+	}, nil
+}
+
+// Languages returns the languages saved in the snapshot.
+func (s *snapshot) Languages() ([]poeditor.Language, error) {
+	langs := []poeditor.Language{}
+	for _, lang := range s.metadata.Languages {
+		langs = append(langs, poeditor.Language{
+			Name:         lang.Name,
+			Code:         lang.Code,
+			Percentage:   lang.Percentage,
+			Translations: lang.Translations,
+			Updated:      lang.Updated,
+		})
+	}
+
+	return langs, nil
+}
+
+func snapshotExportFileName(langCode string) string {
+	return langCode + ".json"
+}
+
+// SaveSnapshot downloads the JSON exports of given languages to dir, along with the snapshot metadata.
+// Files are saved only if every language was downloaded, the same as in ExportLanguages.
+func (c *poeCommand) SaveSnapshot(ctx context.Context, dir string, langs []poeditor.Language) error {
+	c.cleanStagingDirs(dir)
+
+	staging, err := newStagingDir(dir)
+	if err != nil {
+		c.log.Error("creating staging directory failed: " + err.Error())
+		return err
+	}
+	defer staging.Discard()
+
+	metadata := &snapshotMetadata{
+		ProjectID:      c.options.ProjectID,
+		CreatedAt:      time.Now().UTC().Truncate(time.Second),
+		Poe2ArbVersion: Version,
+	}
+	for _, lang := range langs {
+		metadata.Languages = append(metadata.Languages, snapshotLanguage{
+			Name:         lang.Name,
+			Code:         lang.Code,
+			Percentage:   lang.Percentage,
+			Translations: lang.Translations,
+			Updated:      lang.Updated,
+		})
+	}
+
+	err = c.forEachLanguage(ctx, langs, func(ctx context.Context, log *log.Logger, lang poeditor.Language, _ flutter.Locale, _ bool) error {
+		logSub := log.Info("fetching JSON export for %s (%s)", lang.Name, lang.Code).Sub()
+		export, err := c.FetchExport(ctx, logSub, lang.Code)
+		if err != nil {
+			return err
+		}
+		defer export.Close()
+
+		file, err := os.Create(staging.Path(snapshotExportFileName(lang.Code)))
+		if err != nil {
+			logSub.Error("creating file failed: " + err.Error())
+			return err
+		}
+		defer file.Close()
+
+		if _, err := io.Copy(file, export); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logSub.Error("downloading failed: " + err.Error())
+			}
+			return err
+		}
+
+		if err := file.Close(); err != nil {
+			return err
+		}
+
+		logSub.Success("saved")
+
+		return nil
+	})
+	if err != nil {
+		c.log.Error("no snapshot files were changed")
+		return err
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding snapshot metadata: %w", err)
+	}
+	if err := os.WriteFile(staging.Path(snapshotMetadataFile), data, 0o666); err != nil {
+		c.log.Error("writing snapshot metadata failed: " + err.Error())
+		return err
+	}
+
+	logSub := c.log.Info("saving snapshot to %s", dir).Sub()
+	if err := staging.Commit(); err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	return nil
+}
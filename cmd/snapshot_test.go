@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestSnapshot(t *testing.T) string {
+	dir := t.TempDir()
+
+	metadata := snapshotMetadata{
+		ProjectID: "123",
+		CreatedAt: time.Date(2024, 5, 4, 14, 21, 41, 0, time.UTC),
+		Languages: []snapshotLanguage{
+			{Name: "English", Code: "en", Percentage: 100, Translations: 2},
+			{Name: "Polish", Code: "pl", Percentage: 50, Translations: 1},
+		},
+	}
+	data, err := json.Marshal(metadata)
+	assert.NoError(t, err)
+
+	files := map[string]string{
+		snapshotMetadataFile: string(data),
+		"en.json": `[
+			{"term": "greeting", "definition": "Hello, {name}!", "term_plural": ""},
+			{"term": "farewell", "definition": "Bye!", "term_plural": ""}
+		]`,
+		"pl.json": `[
+			{"term": "greeting", "definition": "Cześć, {name}!", "term_plural": ""},
+			{"term": "farewell", "definition": "", "term_plural": ""}
+		]`,
+	}
+	for name, contents := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o666)
+		assert.NoError(t, err)
+	}
+
+	return dir
+}
+
+func newTestSnapshotCommand(t *testing.T, snapshotDir string) *poeCommand {
+	c, err := NewPoeCommand(&poeOptions{
+		ARBPrefix:           "app_",
+		Naming:              poe2arb.NamingKeep,
+		TemplateLocale:      flutter.Locale{Language: "en"},
+		OutputDir:           t.TempDir(),
+		Concurrency:         1,
+		PlaceholderMismatch: poe2arb.PlaceholderMismatchWarn,
+		IncompletePlurals:   poe2arb.IncompletePluralWarn,
+		LowCoverage:         lowCoverageFail,
+		FromSnapshot:        snapshotDir,
+	}, log.New(new(bytes.Buffer)))
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestExportLanguagesFromSnapshot(t *testing.T) {
+	c := newTestSnapshotCommand(t, writeTestSnapshot(t))
+
+	langs, err := c.GetExportLanguages()
+	assert.NoError(t, err)
+	assert.Len(t, langs, 2)
+
+	err = c.ExportLanguages(context.Background(), langs)
+	assert.NoError(t, err)
+
+	en, err := os.ReadFile(filepath.Join(c.options.OutputDir, "app_en.arb"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(en), `"greeting": "Hello, {name}!"`)
+
+	pl, err := os.ReadFile(filepath.Join(c.options.OutputDir, "app_pl.arb"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n    \"@@locale\": \"pl\",\n    \"greeting\": \"Cześć, {name}!\"\n}\n", string(pl))
+}
+
+func TestSaveSnapshot(t *testing.T) {
+	source := writeTestSnapshot(t)
+	c := newTestSnapshotCommand(t, source)
+
+	langs, err := c.GetExportLanguages()
+	assert.NoError(t, err)
+
+	target := t.TempDir()
+	err = c.SaveSnapshot(context.Background(), target, langs)
+	assert.NoError(t, err)
+
+	saved, err := openSnapshot(target)
+	assert.NoError(t, err)
+	savedLangs, err := saved.Languages()
+	assert.NoError(t, err)
+	assert.Equal(t, langs, savedLangs)
+
+	for _, name := range []string{"en.json", "pl.json"} {
+		expected, err := os.ReadFile(filepath.Join(source, name))
+		assert.NoError(t, err)
+
+		actual, err := os.ReadFile(filepath.Join(target, name))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestOpenSnapshotErrors(t *testing.T) {
+	_, err := openSnapshot(t.TempDir())
+	assert.ErrorContains(t, err, "reading snapshot")
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, snapshotMetadataFile), []byte("{"), 0o666))
+
+	_, err = openSnapshot(dir)
+	assert.ErrorContains(t, err, "decoding snapshot metadata")
+}
+
+func TestSnapshotReadOnly(t *testing.T) {
+	s, err := openSnapshot(writeTestSnapshot(t))
+	assert.NoError(t, err)
+
+	err = s.Upload("en", strings.NewReader("[]"), true)
+	assert.ErrorIs(t, err, source.ErrReadOnly)
+
+	err = s.AddLanguage("de")
+	assert.ErrorIs(t, err, source.ErrReadOnly)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// stagingDir collects files to be moved to the target directory all at once.
+//
+// It is created inside the target directory, so that moving the files
+// is a rename on the same filesystem.
+type stagingDir struct {
+	dir       string
+	targetDir string
+
+	mu    sync.Mutex
+	files []string
+	// removed are the files removed from the target directory on Commit.
+	removed []string
+	// moved are the files moved by the last Commit, which Rollback restores.
+	moved []movedFile
+}
+
+type movedFile struct {
+	name      string
+	backedUp  bool
+	committed bool
+}
+
+const (
+	// stagingDirPrefix starts the names of staging directories.
+	stagingDirPrefix = ".poe2arb-staging-"
+	// backupDirName is the directory in the staging directory files replaced by Commit are moved to.
+	backupDirName = ".backup"
+)
+
+func newStagingDir(targetDir string) (*stagingDir, error) {
+	dir, err := os.MkdirTemp(targetDir, stagingDirPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("creating staging directory: %w", err)
+	}
+
+	return &stagingDir{
+		dir:       dir,
+		targetDir: targetDir,
+	}, nil
+}
+
+// Path returns the staging path of a file named fileName.
+// The file will be moved to the target directory on Commit.
+func (s *stagingDir) Path(fileName string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files = append(s.files, fileName)
+
+	return filepath.Join(s.dir, fileName)
+}
+
+// Remove marks the file named fileName to be removed from the target directory on Commit.
+func (s *stagingDir) Remove(fileName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.removed = append(s.removed, fileName)
+}
+
+func (s *stagingDir) backupDir() string {
+	return filepath.Join(s.dir, backupDirName)
+}
+
+// Commit moves all staged files to the target directory, replacing existing ones,
+// and removes the files marked with Remove. If any move fails, the already replaced
+// and removed files are restored.
+func (s *stagingDir) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Mkdir(s.backupDir(), 0o700); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+
+	s.moved = nil
+	for _, name := range s.files {
+		target := filepath.Join(s.targetDir, name)
+		f := movedFile{name: name}
+
+		if err := os.Rename(target, filepath.Join(s.backupDir(), name)); err == nil {
+			f.backedUp = true
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return errors.Join(fmt.Errorf("backing up %s: %w", name, err), s.rollback())
+		}
+		s.moved = append(s.moved, f)
+
+		if err := os.Rename(filepath.Join(s.dir, name), target); err != nil {
+			return errors.Join(fmt.Errorf("moving %s: %w", name, err), s.rollback())
+		}
+		s.moved[len(s.moved)-1].committed = true
+	}
+
+	for _, name := range s.removed {
+		err := os.Rename(filepath.Join(s.targetDir, name), filepath.Join(s.backupDir(), name))
+		if err == nil {
+			s.moved = append(s.moved, movedFile{name: name, backedUp: true})
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return errors.Join(fmt.Errorf("removing %s: %w", name, err), s.rollback())
+		}
+	}
+
+	s.files = nil
+	s.removed = nil
+
+	return nil
+}
+
+// Rollback restores the files replaced by the last Commit. It must be called before Discard,
+// which removes the backups.
+func (s *stagingDir) Rollback() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.rollback()
+}
+
+func (s *stagingDir) rollback() error {
+	var errs []error
+	for i := len(s.moved) - 1; i >= 0; i-- {
+		f := s.moved[i]
+		target := filepath.Join(s.targetDir, f.name)
+
+		if f.committed {
+			if err := os.Remove(target); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if f.backedUp {
+			if err := os.Rename(filepath.Join(s.backupDir(), f.name), target); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	s.moved = nil
+
+	return errors.Join(errs...)
+}
+
+// commitStagingDirs commits all staging directories, so that the files of all of them
+// are replaced or none are. If any commit fails, the already committed ones are rolled back.
+func commitStagingDirs(stagings []*stagingDir) error {
+	for i, staging := range stagings {
+		if err := staging.Commit(); err != nil {
+			errs := []error{err}
+			for j := i - 1; j >= 0; j-- {
+				errs = append(errs, stagings[j].Rollback())
+			}
+			return errors.Join(errs...)
+		}
+	}
+
+	return nil
+}
+
+// Discard removes the staging directory with all files that were not committed.
+func (s *stagingDir) Discard() error {
+	return os.RemoveAll(s.dir)
+}
+
+// cleanStagingDirs removes staging directories left in the target directory by interrupted runs.
+// Backed up files missing in the target directory are restored first, as the interrupted Commit
+// could have moved them away before moving the new ones in. Returns names of the restored files.
+func cleanStagingDirs(targetDir string) (restored []string, err error) {
+	dirs, err := filepath.Glob(filepath.Join(targetDir, stagingDirPrefix+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	for _, dir := range dirs {
+		backupDir := filepath.Join(dir, backupDirName)
+
+		backups, err := os.ReadDir(backupDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+
+		restoreFailed := false
+		for _, backup := range backups {
+			target := filepath.Join(targetDir, backup.Name())
+			if _, err := os.Lstat(target); !errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if err := os.Rename(filepath.Join(backupDir, backup.Name()), target); err != nil {
+				errs = append(errs, fmt.Errorf("restoring %s: %w", backup.Name(), err))
+				restoreFailed = true
+				continue
+			}
+			restored = append(restored, backup.Name())
+		}
+
+		// Keep the backups that couldn't be restored.
+		if !restoreFailed {
+			if err := os.RemoveAll(dir); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return restored, errors.Join(errs...)
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingDir(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(dir, "app_en.arb"), []byte("old en"), 0o666)
+		assert.NoError(t, err)
+
+		return dir
+	}
+
+	stage := func(t *testing.T, staging *stagingDir) {
+		err := os.WriteFile(staging.Path("app_en.arb"), []byte("new en"), 0o666)
+		assert.NoError(t, err)
+
+		err = os.WriteFile(staging.Path("app_pl.arb"), []byte("new pl"), 0o666)
+		assert.NoError(t, err)
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		dir := setup(t)
+
+		staging, err := newStagingDir(dir)
+		assert.NoError(t, err)
+
+		stage(t, staging)
+
+		assert.NoError(t, staging.Commit())
+		assert.NoError(t, staging.Discard())
+
+		assertFileContents(t, filepath.Join(dir, "app_en.arb"), "new en")
+		assertFileContents(t, filepath.Join(dir, "app_pl.arb"), "new pl")
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		dir := setup(t)
+
+		staging, err := newStagingDir(dir)
+		assert.NoError(t, err)
+
+		stage(t, staging)
+
+		assert.NoError(t, staging.Discard())
+
+		assertFileContents(t, filepath.Join(dir, "app_en.arb"), "old en")
+		assert.NoFileExists(t, filepath.Join(dir, "app_pl.arb"))
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("commit failure restores files", func(t *testing.T) {
+		dir := setup(t)
+
+		staging, err := newStagingDir(dir)
+		assert.NoError(t, err)
+
+		stage(t, staging)
+
+		// the second staged file cannot be moved, as it no longer exists
+		assert.NoError(t, os.Remove(filepath.Join(staging.dir, "app_pl.arb")))
+
+		assert.Error(t, staging.Commit())
+		assert.NoError(t, staging.Discard())
+
+		assertFileContents(t, filepath.Join(dir, "app_en.arb"), "old en")
+		assert.NoFileExists(t, filepath.Join(dir, "app_pl.arb"))
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		dir := setup(t)
+
+		staging, err := newStagingDir(dir)
+		assert.NoError(t, err)
+
+		assert.NoError(t, os.WriteFile(staging.Path("app_pl.arb"), []byte("new pl"), 0o666))
+		staging.Remove("app_en.arb")
+		staging.Remove("app_de.arb")
+
+		assert.NoError(t, staging.Commit())
+		assert.NoFileExists(t, filepath.Join(dir, "app_en.arb"))
+		assertFileContents(t, filepath.Join(dir, "app_pl.arb"), "new pl")
+
+		assert.NoError(t, staging.Rollback())
+		assert.NoError(t, staging.Discard())
+		assertFileContents(t, filepath.Join(dir, "app_en.arb"), "old en")
+		assert.NoFileExists(t, filepath.Join(dir, "app_pl.arb"))
+	})
+
+	t.Run("leftovers of an interrupted run are cleaned up", func(t *testing.T) {
+		dir := setup(t)
+
+		// app_en.arb was committed, app_pl.arb was backed up, but not replaced yet.
+		leftover := filepath.Join(dir, stagingDirPrefix+"123")
+		writeTestFiles(t, leftover, map[string]string{
+			"app_de.arb":         "new de",
+			".backup/app_en.arb": "older en",
+			".backup/app_pl.arb": "old pl",
+		})
+
+		restored, err := cleanStagingDirs(dir)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"app_pl.arb"}, restored)
+		assertFileContents(t, filepath.Join(dir, "app_en.arb"), "old en")
+		assertFileContents(t, filepath.Join(dir, "app_pl.arb"), "old pl")
+		assert.NoDirExists(t, leftover)
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("failure of another directory rolls back committed ones", func(t *testing.T) {
+		dir, otherDir := setup(t), setup(t)
+
+		staging, err := newStagingDir(dir)
+		assert.NoError(t, err)
+		stage(t, staging)
+
+		otherStaging, err := newStagingDir(otherDir)
+		assert.NoError(t, err)
+		stage(t, otherStaging)
+		assert.NoError(t, os.Remove(filepath.Join(otherStaging.dir, "app_pl.arb")))
+
+		assert.Error(t, commitStagingDirs([]*stagingDir{staging, otherStaging}))
+		assert.NoError(t, staging.Discard())
+		assert.NoError(t, otherStaging.Discard())
+
+		for _, d := range []string{dir, otherDir} {
+			assertFileContents(t, filepath.Join(d, "app_en.arb"), "old en")
+			assert.NoFileExists(t, filepath.Join(d, "app_pl.arb"))
+		}
+	})
+}
+
+func assertFileContents(t *testing.T, path, expected string) {
+	t.Helper()
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(contents))
+}
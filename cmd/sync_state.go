@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/leancodepl/poe2arb/poeditor"
+)
+
+// syncState records what the last poe run exported, so that languages
+// which haven't changed in POEditor since then are not downloaded again.
+type syncState struct {
+	// Options is a fingerprint of the options the ARB files were generated with.
+	// Changing any of them makes all languages stale.
+	Options string `json:"options"`
+	// Languages are indexed by the POEditor language code.
+	Languages map[string]*languageState `json:"languages"`
+}
+
+type languageState struct {
+	// Updated is the POEditor time of the last translation change.
+	Updated time.Time `json:"updated"`
+	// Translations and Percentage change when terms are added or deleted,
+	// which doesn't always change Updated.
+	Translations int     `json:"translations"`
+	Percentage   float64 `json:"percentage"`
+	// File is the ARB file name and Hash is the hash of its contents.
+	File string `json:"file"`
+	Hash string `json:"hash"`
+}
+
+func newSyncState() *syncState {
+	return &syncState{Languages: map[string]*languageState{}}
+}
+
+// loadSyncState reads the state file. A missing file gives an empty state.
+func loadSyncState(path string) (*syncState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return newSyncState(), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading state file: %w", err)
+	}
+
+	state := newSyncState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decoding state file: %w", err)
+	}
+	if state.Languages == nil {
+		state.Languages = map[string]*languageState{}
+	}
+
+	return state, nil
+}
+
+func (s *syncState) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state file: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o666); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
+	}
+
+	return nil
+}
+
+// unchanged reports whether the language has the same POEditor update time and translation counts
+// as in the state and its ARB file in dir wasn't modified since, so exporting it again would give
+// the same file.
+func (s *syncState) unchanged(options string, lang poeditor.Language, dir string) bool {
+	if s.Options != options || lang.Updated.IsZero() {
+		return false
+	}
+
+	state, ok := s.Languages[lang.Code]
+	if !ok || !state.Updated.Equal(lang.Updated) ||
+		state.Translations != lang.Translations || state.Percentage != lang.Percentage {
+		return false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, state.File))
+	if err != nil {
+		return false
+	}
+
+	return hashContents(data) == state.Hash
+}
+
+func hashContents(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// optionsFingerprint hashes the options affecting the generated files, so that changing
+// any of them exports all languages again.
+func optionsFingerprint(options *poeOptions) string {
+	data, _ := json.Marshal([]any{
+		Version,
+		options.ProjectID,
+		options.FromSnapshot,
+		options.SourceDir,
+		options.TermPrefix,
+		options.OverridePrefixes,
+		options.Naming,
+		options.ARBPrefix,
+		options.TemplateLocale.String(),
+		options.OutputDir,
+		options.OverrideLangs,
+		options.RequireResourceAttributes,
+		options.UseEscaping,
+		options.StrictSyntax,
+		options.PlaceholderMismatch,
+		options.IncompletePlurals,
+		options.MinCoverage,
+		options.LowCoverage,
+		options.Flavors,
+		options.NativeStrings,
+		options.PlatformLocales,
+		options.Pseudo,
+	})
+
+	return hashContents(data)
+}
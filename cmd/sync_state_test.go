@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncState(t *testing.T) {
+	updated := time.Date(2024, 5, 4, 14, 21, 41, 0, time.UTC)
+	pl := poeditor.Language{Code: "pl", Translations: 10, Percentage: 50, Updated: updated}
+
+	setup := func(t *testing.T) (dir string, state *syncState) {
+		dir = t.TempDir()
+
+		err := os.WriteFile(filepath.Join(dir, "app_pl.arb"), []byte("pl"), 0o666)
+		assert.NoError(t, err)
+
+		state = newSyncState()
+		state.Options = "options"
+		state.Languages["pl"] = &languageState{
+			Updated:      updated,
+			Translations: 10,
+			Percentage:   50,
+			File:         "app_pl.arb",
+			Hash:         hashContents([]byte("pl")),
+		}
+
+		return dir, state
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		state, err := loadSyncState(filepath.Join(t.TempDir(), "state.json"))
+
+		assert.NoError(t, err)
+		assert.Empty(t, state.Languages)
+	})
+
+	t.Run("save and load", func(t *testing.T) {
+		_, state := setup(t)
+		path := filepath.Join(t.TempDir(), ".dart_tool", "poe2arb", "state.json")
+
+		assert.NoError(t, state.save(path))
+
+		loaded, err := loadSyncState(path)
+		assert.NoError(t, err)
+		assert.Equal(t, state.Options, loaded.Options)
+		assert.True(t, updated.Equal(loaded.Languages["pl"].Updated))
+		assert.Equal(t, state.Languages["pl"].Hash, loaded.Languages["pl"].Hash)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		dir, state := setup(t)
+
+		assert.True(t, state.unchanged("options", pl, dir))
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		dir, state := setup(t)
+
+		with := func(change func(lang *poeditor.Language)) poeditor.Language {
+			lang := pl
+			change(&lang)
+			return lang
+		}
+
+		assert.False(t, state.unchanged("other options", pl, dir), "options")
+		assert.False(t, state.unchanged("options", with(func(l *poeditor.Language) { l.Updated = updated.Add(time.Minute) }), dir), "updated")
+		assert.False(t, state.unchanged("options", with(func(l *poeditor.Language) { l.Updated = time.Time{} }), dir), "never updated")
+		assert.False(t, state.unchanged("options", with(func(l *poeditor.Language) { l.Percentage = 55.56 }), dir), "term deleted")
+		assert.False(t, state.unchanged("options", with(func(l *poeditor.Language) { l.Translations = 9 }), dir), "translation deleted")
+		assert.False(t, state.unchanged("options", with(func(l *poeditor.Language) { l.Code = "de" }), dir), "unknown language")
+
+		err := os.WriteFile(filepath.Join(dir, "app_pl.arb"), []byte("edited"), 0o666)
+		assert.NoError(t, err)
+		assert.False(t, state.unchanged("options", pl, dir), "edited file")
+
+		err = os.Remove(filepath.Join(dir, "app_pl.arb"))
+		assert.NoError(t, err)
+		assert.False(t, state.unchanged("options", pl, dir), "removed file")
+	})
+}
+
+func TestChangedLanguages(t *testing.T) {
+	updated := time.Date(2024, 5, 4, 14, 21, 41, 0, time.UTC)
+	en := poeditor.Language{Name: "English", Code: "en", Updated: updated}
+	pl := poeditor.Language{Name: "Polish", Code: "pl", Updated: updated}
+	de := poeditor.Language{Name: "German", Code: "de", Updated: updated}
+
+	newCommand := func(t *testing.T, force bool) *poeCommand {
+		dir := t.TempDir()
+		options := &poeOptions{
+			ARBPrefix:      "app_",
+			TemplateLocale: flutter.Locale{Language: "en"},
+			OutputDir:      dir,
+			Force:          force,
+		}
+
+		state := newSyncState()
+		state.Options = optionsFingerprint(options)
+		for _, lang := range []poeditor.Language{en, pl, de} {
+			fileName := "app_" + lang.Code + ".arb"
+			err := os.WriteFile(filepath.Join(dir, fileName), []byte(lang.Code), 0o666)
+			assert.NoError(t, err)
+
+			state.Languages[lang.Code] = &languageState{Updated: lang.Updated, File: fileName, Hash: hashContents([]byte(lang.Code))}
+		}
+
+		return &poeCommand{options: options, log: log.New(new(bytes.Buffer)), state: state}
+	}
+
+	t.Run("nothing changed", func(t *testing.T) {
+		c := newCommand(t, false)
+
+		assert.Empty(t, c.changedLanguages([]poeditor.Language{en, pl, de}))
+	})
+
+	t.Run("translation changed", func(t *testing.T) {
+		c := newCommand(t, false)
+		changedPL := pl
+		changedPL.Updated = updated.Add(time.Hour)
+
+		changed := c.changedLanguages([]poeditor.Language{en, changedPL, de})
+
+		assert.Equal(t, []poeditor.Language{en, changedPL}, changed)
+	})
+
+	t.Run("template changed", func(t *testing.T) {
+		c := newCommand(t, false)
+		changedEN := en
+		changedEN.Updated = updated.Add(time.Hour)
+
+		changed := c.changedLanguages([]poeditor.Language{pl, changedEN, de})
+
+		assert.Equal(t, []poeditor.Language{changedEN}, changed)
+	})
+
+	t.Run("source changed", func(t *testing.T) {
+		c := newCommand(t, false)
+		c.options.FromSnapshot = "snapshot"
+
+		assert.Equal(t, []poeditor.Language{en, pl, de}, c.changedLanguages([]poeditor.Language{en, pl, de}))
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		c := newCommand(t, true)
+
+		assert.Equal(t, []poeditor.Language{en, pl, de}, c.changedLanguages([]poeditor.Language{en, pl, de}))
+	})
+}
+
+func TestOptionsFingerprint(t *testing.T) {
+	newOptions := func() *poeOptions {
+		return &poeOptions{ARBPrefix: "app_", TemplateLocale: flutter.Locale{Language: "en"}, OutputDir: "lib/l10n"}
+	}
+	fingerprint := optionsFingerprint(newOptions())
+
+	changes := map[string]func(o *poeOptions){
+		"langs":            func(o *poeOptions) { o.OverrideLangs = []string{"en", "pl"} },
+		"strict syntax":    func(o *poeOptions) { o.StrictSyntax = true },
+		"flavors":          func(o *poeOptions) { o.Flavors = []*flavorOptions{{Name: "brandx", TermPrefixes: []string{"brandX"}}} },
+		"platform locales": func(o *poeOptions) { o.PlatformLocales = &platformLocalesOptions{IOSDir: "ios/Runner"} },
+		"pseudo-locale":    func(o *poeOptions) { o.Pseudo = &pseudoOptions{flutter.Locale{Language: "en", Country: "XA"}, 30} },
+	}
+
+	for name, change := range changes {
+		options := newOptions()
+		change(options)
+
+		assert.NotEqual(t, fingerprint, optionsFingerprint(options), name)
+	}
+
+	assert.Equal(t, fingerprint, optionsFingerprint(newOptions()))
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/spf13/cobra"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+var usageCmd = &cobra.Command{
+	Use: "usage",
+	Short: "Finds POEditor terms never used in the Dart source and used messages without terms. " +
+		"Must be run from the Flutter project root directory or its subdirectory.",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE:          runUsage,
+	PreRunE:       versionGuard.GetFlutterConfigAndEnsureSufficientVersion,
+}
+
+const (
+	usagePatternFlag = "pattern"
+	unusedFlag       = "unused"
+	yesFlag          = "yes"
+
+	// unusedTermTag is the POEditor tag added to terms never used in the Dart source.
+	unusedTermTag = "unused"
+)
+
+func init() {
+	usageCmd.Flags().StringP(projectIDFlag, "p", "", "POEditor project ID")
+	usageCmd.Flags().StringP(tokenFlag, "t", "", "POEditor API token")
+	usageCmd.Flags().StringP(termPrefixFlag, "", "", "POEditor term prefix")
+	usageCmd.Flags().StringP(namingFlag, "", "",
+		"How term names are turned into message names: keep, dot-to-camel or snake-to-camel [default: keep]")
+	usageCmd.Flags().StringP(outputDirFlag, "o", "", `ARB files directory [default: "."]`)
+	usageCmd.Flags().String(fromSnapshotFlag, "", "Read terms from a snapshot saved with the snapshot command, instead of downloading them")
+	usageCmd.Flags().String(sourceDirFlag, "", "Read terms from a directory of POEditor JSON or ARB files, instead of downloading them")
+	usageCmd.Flags().StringSlice(usagePatternFlag, []string{},
+		"Regular expression matching expressions messages are accessed on, e.g. context\\.l10n "+
+			"[default: AppLocalizations.of(context) and names declared with it, e.g. l10n]")
+	usageCmd.Flags().String(unusedFlag, obsoleteKeep,
+		fmt.Sprintf("What to do with unused POEditor terms: %s, %s (with %q tag) or %s",
+			obsoleteKeep, obsoleteTag, unusedTermTag, obsoleteDelete))
+	usageCmd.Flags().Bool(yesFlag, false,
+		fmt.Sprintf("Confirm deleting unused POEditor terms with --%s %s, after reviewing them", unusedFlag, obsoleteDelete))
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	log := getLogger(cmd)
+
+	logSub := log.Info("loading options").Sub()
+
+	unused, _ := cmd.Flags().GetString(unusedFlag)
+	if !slices.Contains([]string{obsoleteKeep, obsoleteTag, obsoleteDelete}, unused) {
+		err := fmt.Errorf("invalid --%s value %q, must be one of: %s, %s, %s",
+			unusedFlag, unused, obsoleteKeep, obsoleteTag, obsoleteDelete)
+		logSub.Error(err.Error())
+		return err
+	}
+
+	// Usages are found by patterns, so messages accessed otherwise are reported as unused.
+	// Deleting them has to be confirmed after reviewing the report.
+	if yes, _ := cmd.Flags().GetBool(yesFlag); unused == obsoleteDelete && !yes {
+		err := fmt.Errorf("--%s %s deletes POEditor terms: review the unused terms reported without it first, "+
+			"then confirm with --%s", unusedFlag, obsoleteDelete, yesFlag)
+		logSub.Error(err.Error())
+		return err
+	}
+
+	sel, err := getOptionsSelector(cmd)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	options, err := sel.SelectOptions()
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	poeCmd, err := NewPoeCommand(options, log)
+	if err != nil {
+		logSub.Error(err.Error())
+		return err
+	}
+
+	if unused != obsoleteKeep {
+		if _, err := poeCmd.termsEditor(); err != nil {
+			err := fmt.Errorf("--%s %s can't be used: %w", unusedFlag, unused, err)
+			logSub.Error(err.Error())
+			return err
+		}
+	}
+
+	flutterCfg := flutterConfigFromCommand(cmd)
+
+	patterns, _ := cmd.Flags().GetStringSlice(usagePatternFlag)
+	if len(patterns) == 0 {
+		patterns = flutterCfg.L10n.POEditorUsagePatterns
+	}
+
+	logSub = log.Info("scanning Dart files in %s", filepath.Join(flutterCfg.RootDir, "lib")).Sub()
+	if len(patterns) == 0 {
+		patterns, err = flutterCfg.DefaultUsagePatterns()
+		if err != nil {
+			logSub.Error("failed: " + err.Error())
+			return err
+		}
+	}
+
+	usages, err := flutterCfg.FindMessageUsages(patterns)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+	logSub.Info("found %d message usages", len(usages))
+
+	templatePath := filepath.Join(options.OutputDir, poeCmd.arbFileName(options.TemplateLocale))
+	logSub = log.Info("reading template ARB %s", templatePath).Sub()
+	templateMessages, err := readARBMessageNames(templatePath)
+	if err != nil {
+		logSub.Error("failed: " + err.Error())
+		return err
+	}
+
+	log.Info("fetching project languages")
+	langs, err := poeCmd.getProjectLanguages()
+	if err != nil {
+		log.Error("failed fetching languages: " + err.Error())
+		return err
+	}
+
+	lang, ok := findLanguage(langs, options.TemplateLocale)
+	if !ok {
+		err := fmt.Errorf("template language %s not found in the POEditor project", options.TemplateLocale)
+		log.Error(err.Error())
+		return err
+	}
+
+	logSub = log.Info("fetching JSON export for %s (%s)", lang.Name, lang.Code).Sub()
+	terms, err := poeCmd.fetchTerms(cmd.Context(), logSub, lang.Code)
+	if err != nil {
+		return err
+	}
+
+	report := compareUsages(usages, templateMessages, terms, options.TermPrefix, options.Naming)
+	report.Report(log)
+
+	unusedTerms := report.UnusedTerms()
+	if len(unusedTerms) > 0 {
+		switch unused {
+		case obsoleteTag:
+			logSub := log.Info("tagging %d unused terms with %q", len(unusedTerms), unusedTermTag).Sub()
+			if err := poeCmd.tagTerms(logSub, report.IsUnused, unusedTermTag); err != nil {
+				return err
+			}
+
+		case obsoleteDelete:
+			logSub := log.Info("deleting %d unused terms", len(unusedTerms)).Sub()
+			if err := poeCmd.deleteTerms(logSub, unusedTerms); err != nil {
+				return err
+			}
+		}
+	}
+
+	log.Success("done")
+
+	return nil
+}
+
+// readARBMessageNames returns names of the messages in the ARB file.
+func readARBMessageNames(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	arb := orderedmap.New[string, any]()
+	if err := json.Unmarshal(data, &arb); err != nil {
+		return nil, fmt.Errorf("decoding ARB: %w", err)
+	}
+
+	return arbMessageNames(arb), nil
+}
+
+// usageReport describes differences between the messages used in the Dart source
+// and the ones defined in POEditor and the template ARB.
+type usageReport struct {
+	// Unused are messages never used in the source.
+	Unused []*unusedMessage
+	// Missing are messages used in the source, but with no POEditor term.
+	Missing []*missingMessage
+}
+
+type unusedMessage struct {
+	Name string
+	// Term is nil when the message is only in the template ARB.
+	Term *convert.POETerm
+}
+
+type missingMessage struct {
+	Name string
+	// InTemplate is true when the message is in the template ARB, but not yet in POEditor.
+	InTemplate bool
+	Usages     []flutter.MessageUsage
+}
+
+// compareUsages compares message usages with the messages of the template ARB and
+// the POEditor terms with the given prefix, named with the naming strategy.
+func compareUsages(
+	usages []flutter.MessageUsage,
+	templateMessages []string,
+	terms []*convert.POETerm,
+	termPrefix string,
+	naming poe2arb.NamingStrategy,
+) *usageReport {
+	report := &usageReport{}
+
+	used := map[string]bool{}
+	for _, usage := range usages {
+		used[usage.Name] = true
+	}
+
+	termMessages := map[string]bool{}
+	for _, term := range terms {
+		if termPrefixOf(term.Term) != termPrefix {
+			continue
+		}
+
+		name, err := poe2arb.MessageName(termWithPrefixRegexp.ReplaceAllString(term.Term, ""), naming)
+		if err != nil {
+			// Invalid terms are reported by the poe command.
+			continue
+		}
+		termMessages[name] = true
+
+		if !used[name] {
+			report.Unused = append(report.Unused, &unusedMessage{Name: name, Term: term})
+		}
+	}
+
+	for _, name := range templateMessages {
+		if !termMessages[name] && !used[name] {
+			report.Unused = append(report.Unused, &unusedMessage{Name: name})
+		}
+	}
+
+	missingByName := map[string]*missingMessage{}
+	for _, usage := range usages {
+		if termMessages[usage.Name] {
+			continue
+		}
+
+		missing, ok := missingByName[usage.Name]
+		if !ok {
+			missing = &missingMessage{Name: usage.Name, InTemplate: slices.Contains(templateMessages, usage.Name)}
+			missingByName[usage.Name] = missing
+			report.Missing = append(report.Missing, missing)
+		}
+		missing.Usages = append(missing.Usages, usage)
+	}
+
+	return report
+}
+
+// UnusedTerms returns the POEditor terms of the unused messages.
+func (r *usageReport) UnusedTerms() []*convert.POETerm {
+	var terms []*convert.POETerm
+	for _, message := range r.Unused {
+		if message.Term != nil {
+			terms = append(terms, message.Term)
+		}
+	}
+	return terms
+}
+
+func (r *usageReport) IsUnused(term, termContext string) bool {
+	return slices.ContainsFunc(r.UnusedTerms(), func(t *convert.POETerm) bool {
+		return t.Term == term && t.Context == termContext
+	})
+}
+
+func (r *usageReport) Report(log *log.Logger) {
+	if len(r.Unused) == 0 && len(r.Missing) == 0 {
+		log.Success("all messages are used and have POEditor terms")
+		return
+	}
+
+	if len(r.Unused) > 0 {
+		logSub := log.Warning("%d unused messages", len(r.Unused)).Sub()
+		for _, message := range r.Unused {
+			if message.Term != nil {
+				logSub.Info("%s (term %s)", message.Name, message.Term.Term)
+			} else {
+				logSub.Info("%s (only in template ARB)", message.Name)
+			}
+		}
+	}
+
+	if len(r.Missing) > 0 {
+		logSub := log.Error("%d used messages without POEditor terms", len(r.Missing)).Sub()
+		for _, message := range r.Missing {
+			var locations []string
+			for _, usage := range message.Usages {
+				locations = append(locations, usage.String())
+			}
+
+			if message.InTemplate {
+				logSub.Info("%s: %s (only in template ARB, run poe2arb push)", message.Name, strings.Join(locations, ", "))
+			} else {
+				logSub.Info("%s: %s", message.Name, strings.Join(locations, ", "))
+			}
+		}
+	}
+}
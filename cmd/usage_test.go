@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareUsages(t *testing.T) {
+	terms := []*convert.POETerm{
+		{Term: "app:login.title"},
+		{Term: "app:login.unused"},
+		{Term: "app:Invalid name!"},
+		{Term: "other:settings.title"},
+	}
+	usages := []flutter.MessageUsage{
+		{Name: "loginTitle", File: "lib/login.dart", Line: 3},
+		{Name: "newMessage", File: "lib/login.dart", Line: 7},
+		{Name: "settingsTitle", File: "lib/settings.dart", Line: 2},
+		{Name: "newMessage", File: "lib/settings.dart", Line: 9},
+	}
+	templateMessages := []string{"loginTitle", "loginUnused", "newMessage", "templateOnly"}
+
+	report := compareUsages(usages, templateMessages, terms, "app", poe2arb.NamingDotToCamel)
+
+	assert.Equal(t, []*unusedMessage{
+		{Name: "loginUnused", Term: terms[1]},
+		{Name: "templateOnly"},
+	}, report.Unused)
+	assert.Equal(t, []*missingMessage{
+		{Name: "newMessage", InTemplate: true, Usages: []flutter.MessageUsage{usages[1], usages[3]}},
+		{Name: "settingsTitle", Usages: []flutter.MessageUsage{usages[2]}},
+	}, report.Missing)
+
+	assert.Equal(t, []*convert.POETerm{terms[1]}, report.UnusedTerms())
+	assert.True(t, report.IsUnused("app:login.unused", ""))
+	assert.False(t, report.IsUnused("app:login.title", ""))
+}
+
+func TestUsageDeleteRequiresConfirmation(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String(unusedFlag, obsoleteKeep, "")
+	cmd.Flags().Bool(yesFlag, false, "")
+	assert.NoError(t, cmd.Flags().Parse([]string{"--" + unusedFlag, obsoleteDelete}))
+	cmd.SetContext(context.WithValue(context.Background(), loggerKey{}, log.New(new(bytes.Buffer))))
+
+	err := runUsage(cmd, nil)
+
+	assert.ErrorContains(t, err, "then confirm with --yes")
+}
@@ -23,7 +23,10 @@ func (a *ARBMessageAttributes) IsEmpty() bool {
 }
 
 type ARBPlaceholder struct {
-	Name   string `json:"-"`
-	Type   string `json:"type,omitempty"`
-	Format string `json:"format,omitempty"`
+	Name               string                              `json:"-"`
+	Type               string                              `json:"type,omitempty"`
+	Format             string                              `json:"format,omitempty"`
+	IsCustomDateFormat string                              `json:"isCustomDateFormat,omitempty"`
+	OptionalParameters *orderedmap.OrderedMap[string, any] `json:"optionalParameters,omitempty"`
+	Example            string                              `json:"example,omitempty"`
 }
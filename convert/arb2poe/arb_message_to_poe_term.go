@@ -1,136 +1,211 @@
 package arb2poe
 
 import (
-	"errors"
-	"regexp"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
 )
 
 func arbMessageToPOETerm(
 	m *convert.ARBMessage,
 	skipPlaceholderDefinitions bool,
 	termPrefix string,
+	options icu.Options,
 ) (*convert.POETerm, error) {
-	translation := m.Translation
+	message, err := icu.Parse(m.Translation, options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+
 	if !skipPlaceholderDefinitions && m.Attributes != nil && m.Attributes.Placeholders != nil {
 		for pair := m.Attributes.Placeholders.Oldest(); pair != nil; pair = pair.Next() {
-			placeholderName, placeholder := pair.Key, pair.Value
+			definePlaceholder(message, pair.Value)
+		}
+	}
 
-			definitionAppend := ""
-			if placeholder.Type != "" {
-				definitionAppend += "," + placeholder.Type
-			}
-			if placeholder.Format != "" {
-				definitionAppend += "," + placeholder.Format
-			}
+	translation := icu.Print(message, options)
 
-			// Only do the replacement for the first occurence (defining the same parameter multiple times is illegal)
-			found := strings.Index(translation, "{"+placeholderName+"}")
-			if found == -1 {
-				continue
-			}
+	var definition convert.POETermDefinition
+	var termPlural string
 
-			index := 1 + found + len(placeholderName)
+	countName, pluralDefinition, err := parseTopLevelPlural(message, options)
+	if err != nil {
+		return nil, err
+	}
 
-			translation = translation[:index] + definitionAppend + translation[index:]
+	if pluralDefinition != nil {
+		definition = convert.POETermDefinition{
+			IsPlural: true,
+			Plural:   pluralDefinition,
 		}
+
+		// Any plural name works for the default count placeholder,
+		// others are recognized by the name in brackets.
+		termPlural = "."
+		if countName != convert.DefaultCountPlaceholderName {
+			termPlural = "{" + countName + "}"
+		}
+	} else {
+		// Plurals nested in the text or using categories unsupported by POEditor
+		// are kept as an ICU message in a regular term.
+		definition = convert.POETermDefinition{Value: &translation}
 	}
 
-	var definition convert.POETermDefinition
-	pluralRegexp := regexp.MustCompile(`^{count,\s*plural,\s*(.+)}$`)
-	if matches := pluralRegexp.FindStringSubmatch(translation); len(matches) > 0 {
-		pluralDefinition := &convert.POETermPluralDefinition{}
+	termName := m.Name
+	if termPrefix != "" {
+		termName = termPrefix + ":" + termName
+	}
 
-		pluralCategoryRegexp := regexp.MustCompile(`\s*(=0|=1|=2|zero|one|two|few|many|other)\s*{`)
-		pluralsString := matches[1]
+	// Term comments are shared by all languages, so only the template sets them.
+	var comment string
+	if !skipPlaceholderDefinitions && m.Attributes != nil {
+		comment = m.Attributes.Description
+	}
 
-		for {
-			match := pluralCategoryRegexp.FindStringSubmatch(pluralsString)
-			if len(match) == 0 {
-				break
-			}
+	return &convert.POETerm{
+		Term:       termName,
+		TermPlural: termPlural,
+		Comment:    comment,
+		Definition: definition,
+	}, nil
+}
 
-			pluralCategory := match[1]
+// definePlaceholder adds the placeholder type and format to its first occurrence
+// in the message, as defining the same placeholder multiple times is illegal in POEditor.
+// Select and plural variables are defined by the expressions themselves.
+func definePlaceholder(m icu.Message, placeholder *convert.ARBPlaceholder) {
+	placeholderType, style := placeholderDefinition(placeholder)
+	if placeholderType == "" {
+		return
+	}
 
-			pluralsString = pluralsString[len(match[0]):]
+	defined := false
+	icu.Walk(m, func(node icu.Node) {
+		if n, ok := node.(*icu.Select); ok && n.Name == placeholder.Name {
+			defined = true
+		}
+	})
 
-			depth := 1
-			endLength := 0
-			for {
-				findString := pluralsString[endLength:]
+	icu.Walk(m, func(node icu.Node) {
+		if argument, ok := node.(*icu.Argument); ok && !defined && argument.Name == placeholder.Name && argument.Type == "" {
+			argument.Type, argument.Style = placeholderType, style
+			defined = true
+		}
+	})
+}
 
-				if findString[0] == '\\' {
-					// escape character, ignore next bracket
-					endLength += 2
-					continue
-				} else if findString[0] == '{' {
-					depth++
-				} else if findString[0] == '}' {
-					depth--
-				}
+// placeholderDefinition returns the type and the rest of the POEditor placeholder definition,
+// e.g. double and currency(name: "EUR"),example: "€1.00".
+func placeholderDefinition(placeholder *convert.ARBPlaceholder) (placeholderType, style string) {
+	if placeholder.Type == "" {
+		return "", ""
+	}
 
-				endLength++
+	var parts []string
+
+	switch {
+	case placeholder.IsCustomDateFormat == "true":
+		parts = append(parts, strconv.Quote(placeholder.Format))
+	case placeholder.Format != "":
+		format := placeholder.Format
+		if params := placeholder.OptionalParameters; params != nil {
+			var values []string
+			for pair := params.Oldest(); pair != nil; pair = pair.Next() {
+				values = append(values, pair.Key+": "+optionalParameterValue(pair.Value))
+			}
+			format += "(" + strings.Join(values, ", ") + ")"
+		}
+		parts = append(parts, format)
+	}
 
-				if depth == 0 {
-					break
-				}
+	if placeholder.Example != "" {
+		parts = append(parts, "example: "+strconv.Quote(placeholder.Example))
+	}
 
-			}
+	return placeholder.Type, strings.Join(parts, ",")
+}
 
-			pluralDefinitionValue := pluralsString[:endLength-1] // -1 to remove the closing bracket
-			switch pluralCategory {
-			case "=0", "zero":
-				if pluralDefinition.Zero != nil {
-					return nil, errors.New("multiple definitions for plural category zero")
-				}
-
-				pluralDefinition.Zero = &pluralDefinitionValue
-			case "=1", "one":
-				if pluralDefinition.One != nil {
-					return nil, errors.New("multiple definitions for plural category one")
-				}
-
-				pluralDefinition.One = &pluralDefinitionValue
-			case "=2", "two":
-				if pluralDefinition.Two != nil {
-					return nil, errors.New("multiple definitions for plural category two")
-				}
-
-				pluralDefinition.Two = &pluralDefinitionValue
-			case "few":
-				pluralDefinition.Few = &pluralDefinitionValue
-			case "many":
-				pluralDefinition.Many = &pluralDefinitionValue
-			case "other":
-				pluralDefinition.Other = pluralDefinitionValue
-			}
+func optionalParameterValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
 
-			pluralsString = pluralsString[endLength:]
+// exactPluralCategories are the POEditor plural categories written as exact matches
+// in locales without such category, see convert.POETermPluralDefinition.ToICUMessageFormat.
+var exactPluralCategories = map[string]string{"=0": "zero", "=1": "one", "=2": "two"}
+
+// parseTopLevelPlural returns the POEditor plural definition if the whole message
+// is a single plural expression using only categories supported by POEditor.
+// Otherwise, it returns a nil definition.
+//
+// Exact matches =0, =1 and =2 are always the zero, one and two categories, also in locales
+// having such category, as older poe2arb versions wrote them this way in every locale.
+func parseTopLevelPlural(m icu.Message, options icu.Options) (countName string, plural *convert.POETermPluralDefinition, err error) {
+	var pluralNode *icu.Plural
+	for _, node := range m {
+		switch n := node.(type) {
+		case *icu.Plural:
+			if pluralNode != nil {
+				return "", nil, nil
+			}
+			pluralNode = n
+		case *icu.Text:
+			if strings.TrimSpace(n.Value) != "" {
+				// plural nested in the text
+				return "", nil, nil
+			}
+		default:
+			return "", nil, nil
 		}
+	}
+	if pluralNode == nil {
+		return "", nil, nil
+	}
 
-		definition = convert.POETermDefinition{
-			IsPlural: true,
-			Plural:   pluralDefinition,
+	plural = &convert.POETermPluralDefinition{}
+	for _, c := range pluralNode.Cases {
+		value := icu.Print(c.Message, options)
+
+		key := c.Key
+		if exactCategory, ok := exactPluralCategories[key]; ok {
+			key = exactCategory
 		}
-	} else {
-		definition = convert.POETermDefinition{Value: &translation}
-	}
 
-	var termPlural string
-	if definition.IsPlural {
-		termPlural = "."
-	}
+		var category **string
+		var categoryName string
+		switch key {
+		case "zero":
+			category, categoryName = &plural.Zero, "zero"
+		case "one":
+			category, categoryName = &plural.One, "one"
+		case "two":
+			category, categoryName = &plural.Two, "two"
+		case "few":
+			category, categoryName = &plural.Few, "few"
+		case "many":
+			category, categoryName = &plural.Many, "many"
+		case "other":
+			plural.Other = value
+			continue
+		default:
+			// not representable as POEditor plural
+			return "", nil, nil
+		}
 
-	termName := m.Name
-	if termPrefix != "" {
-		termName = termPrefix + ":" + termName
+		if *category != nil {
+			return "", nil, fmt.Errorf("multiple definitions for plural category %s", categoryName)
+		}
+		*category = &value
 	}
 
-	return &convert.POETerm{
-		Term:       termName,
-		TermPlural: termPlural,
-		Definition: definition,
-	}, nil
+	return pluralNode.Name, plural, nil
 }
@@ -0,0 +1,285 @@
+package arb2poe
+
+import (
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestArbMessageToPOETerm(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Message        *convert.ARBMessage
+		Template       bool
+		UseEscaping    bool
+		ExpectedTerm   *convert.POETerm
+		ExpectedErrMsg string
+	}
+
+	ptr := func(s string) *string { return &s }
+
+	placeholders := func(names ...string) *orderedmap.OrderedMap[string, *convert.ARBPlaceholder] {
+		om := orderedmap.New[string, *convert.ARBPlaceholder]()
+		for i := 0; i < len(names); i += 2 {
+			om.Set(names[i], &convert.ARBPlaceholder{Name: names[i], Type: names[i+1]})
+		}
+		return om
+	}
+
+	testCases := []testCase{
+		{
+			Name:     "description becomes comment in template",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "hello",
+				Translation: "Hello, {name}!",
+				Attributes: &convert.ARBMessageAttributes{
+					Description:  "Greeting",
+					Placeholders: placeholders("name", "String"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "hello",
+				Comment:    "Greeting",
+				Definition: convert.POETermDefinition{Value: ptr("Hello, {name,String}!")},
+			},
+		},
+		{
+			Name:     "description is skipped in non-template",
+			Template: false,
+			Message: &convert.ARBMessage{
+				Name:        "hello",
+				Translation: "Hello, {name}!",
+				Attributes: &convert.ARBMessageAttributes{
+					Description:  "Greeting",
+					Placeholders: placeholders("name", "String"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "hello",
+				Definition: convert.POETermDefinition{Value: ptr("Hello, {name}!")},
+			},
+		},
+		{
+			Name:     "plural",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "apples",
+				Translation: "{count, plural, one {{count} apple} other {{count} apples}}",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: placeholders("count", "int"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "apples",
+				TermPlural: ".",
+				Definition: convert.POETermDefinition{
+					IsPlural: true,
+					Plural: &convert.POETermPluralDefinition{
+						One:   ptr("{count,int} apple"),
+						Other: "{count} apples",
+					},
+				},
+			},
+		},
+		{
+			Name:     "plural exact match of category missing in locale",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "apples",
+				Translation: "{count, plural, =0 {no apples} one {{count} apple} other {{count} apples}}",
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "apples",
+				TermPlural: ".",
+				Definition: convert.POETermDefinition{
+					IsPlural: true,
+					Plural: &convert.POETermPluralDefinition{
+						Zero:  ptr("no apples"),
+						One:   ptr("{count} apple"),
+						Other: "{count} apples",
+					},
+				},
+			},
+		},
+		{
+			Name:     "plural exact match of category present in locale",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "apples",
+				Translation: "{count, plural, =1 {one apple} other {{count} apples}}",
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "apples",
+				TermPlural: ".",
+				Definition: convert.POETermDefinition{
+					IsPlural: true,
+					Plural: &convert.POETermPluralDefinition{
+						One:   ptr("one apple"),
+						Other: "{count} apples",
+					},
+				},
+			},
+		},
+		{
+			Name:     "placeholder options",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "order",
+				Translation: "{price} on {date}",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: orderedmap.New[string, *convert.ARBPlaceholder](orderedmap.WithInitialData(
+						orderedmap.Pair[string, *convert.ARBPlaceholder]{Key: "price", Value: &convert.ARBPlaceholder{
+							Name:   "price",
+							Type:   "double",
+							Format: "currency",
+							OptionalParameters: orderedmap.New[string, any](orderedmap.WithInitialData(
+								orderedmap.Pair[string, any]{Key: "name", Value: "EUR"},
+								orderedmap.Pair[string, any]{Key: "decimalDigits", Value: float64(2)},
+							)),
+							Example: "€1.00",
+						}},
+						orderedmap.Pair[string, *convert.ARBPlaceholder]{Key: "date", Value: &convert.ARBPlaceholder{
+							Name:               "date",
+							Type:               "DateTime",
+							Format:             "EEE, M/d/y",
+							IsCustomDateFormat: "true",
+						}},
+					)),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term: "order",
+				Definition: convert.POETermDefinition{
+					Value: ptr(`{price,double,currency(name: "EUR", decimalDigits: 2),example: "€1.00"} on {date,DateTime,"EEE, M/d/y"}`),
+				},
+			},
+		},
+		{
+			Name:     "select",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "pronoun",
+				Translation: "{gender, select, male {He has {gender} {count}} other {They have {count}}}",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: placeholders("gender", "String", "count", "int"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "pronoun",
+				Definition: convert.POETermDefinition{Value: ptr("{gender, select, male {He has {gender} {count,int}} other {They have {count}}}")},
+			},
+		},
+		{
+			Name:     "plural with custom count placeholder",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "items",
+				Translation: "{itemCount, plural, one {1 item} other {{itemCount} items}}",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: placeholders("itemCount", "int"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "items",
+				TermPlural: "{itemCount}",
+				Definition: convert.POETermDefinition{
+					IsPlural: true,
+					Plural: &convert.POETermPluralDefinition{
+						One:   ptr("1 item"),
+						Other: "{itemCount,int} items",
+					},
+				},
+			},
+		},
+		{
+			Name:     "plural nested in text",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "cart",
+				Translation: "You have {itemCount, plural, one {1 item} other {{itemCount} items}} in {cartName}",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: placeholders("itemCount", "int", "cartName", "String"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term: "cart",
+				Definition: convert.POETermDefinition{
+					Value: ptr("You have {itemCount, plural, one {1 item} other {{itemCount,int} items}} in {cartName,String}"),
+				},
+			},
+		},
+		{
+			Name:     "plural with exact match unsupported by POEditor",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "items",
+				Translation: "{count, plural, =5 {five} other {{count}}}",
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "items",
+				Definition: convert.POETermDefinition{Value: ptr("{count, plural, =5 {five} other {{count}}}")},
+			},
+		},
+		{
+			Name:     "plural with duplicated category",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "items",
+				Translation: "{count, plural, =0 {zero} zero {zero again} other {other}}",
+			},
+			ExpectedErrMsg: "multiple definitions for plural category zero",
+		},
+		{
+			Name:     "plural exact match and category",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "items",
+				Translation: "{count, plural, =1 {one} one {one again} other {other}}",
+			},
+			ExpectedErrMsg: "multiple definitions for plural category one",
+		},
+		{
+			Name:     "unbalanced brackets",
+			Template: true,
+			Message: &convert.ARBMessage{
+				Name:        "apples",
+				Translation: "{count, plural, one {{count} apple} other {{count} apples}",
+			},
+			ExpectedErrMsg: "invalid message: unterminated argument at offset 0",
+		},
+		{
+			Name:        "escaped brackets and apostrophes",
+			Template:    true,
+			UseEscaping: true,
+			Message: &convert.ARBMessage{
+				Name:        "braces",
+				Translation: "Use {braces} like '{this}', it''s easy",
+				Attributes: &convert.ARBMessageAttributes{
+					Placeholders: placeholders("braces", "String"),
+				},
+			},
+			ExpectedTerm: &convert.POETerm{
+				Term:       "braces",
+				Definition: convert.POETermDefinition{Value: ptr("Use {braces,String} like '{this}', it''s easy")},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			term, err := arbMessageToPOETerm(testCase.Message, !testCase.Template, "", icu.Options{Escaping: testCase.UseEscaping, Relaxed: true})
+
+			if testCase.ExpectedErrMsg != "" {
+				assert.EqualError(t, err, testCase.ExpectedErrMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.ExpectedTerm, term)
+		})
+	}
+}
@@ -12,75 +12,131 @@ import (
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
+type arbAttributes struct {
+	Description  string                                             `json:"description,omitempty"`
+	Placeholders *orderedmap.OrderedMap[string, arbPlaceholderJSON] `json:"placeholders,omitempty"`
+}
+
+type arbPlaceholderJSON struct {
+	Type               string                              `json:"type,omitempty"`
+	Format             string                              `json:"format,omitempty"`
+	IsCustomDateFormat any                                 `json:"isCustomDateFormat,omitempty"`
+	OptionalParameters *orderedmap.OrderedMap[string, any] `json:"optionalParameters,omitempty"`
+	Example            string                              `json:"example,omitempty"`
+}
+
+// parseARB reads the ARB file token by token, so that messages and their
+// placeholders are returned in the order they appear in the file.
 func parseARB(r io.Reader) (locale flutter.Locale, messages []*convert.ARBMessage, err error) {
-	var arb map[string]any
-	err = json.NewDecoder(r).Decode(&arb)
-	if err != nil {
-		err = fmt.Errorf("failed to decode ARB: %w", err)
-		return flutter.Locale{}, nil, err
+	dec := json.NewDecoder(r)
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return flutter.Locale{}, nil, fmt.Errorf("failed to decode ARB: %w", err)
 	}
 
-	lang, ok := arb[convert.LocaleKey].(string)
-	if !ok {
-		err = errors.New("missing locale key")
-		return flutter.Locale{}, nil, err
+	var lang string
+	attributes := map[string]*arbAttributes{}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return flutter.Locale{}, nil, fmt.Errorf("failed to decode ARB: %w", err)
+		}
+		key := token.(string)
+
+		switch {
+		case key == convert.LocaleKey:
+			if err := dec.Decode(&lang); err != nil {
+				return flutter.Locale{}, nil, fmt.Errorf("invalid locale value: %w", err)
+			}
+
+		case strings.HasPrefix(key, "@@"):
+			// other global attributes, e.g. @@last_modified
+			var ignored json.RawMessage
+			if err := dec.Decode(&ignored); err != nil {
+				return flutter.Locale{}, nil, fmt.Errorf("failed to decode ARB: %w", err)
+			}
+
+		case strings.HasPrefix(key, "@"):
+			var attrs arbAttributes
+			if err := dec.Decode(&attrs); err != nil {
+				return flutter.Locale{}, nil, fmt.Errorf("failed to decode attributes for %s: %w", key[1:], err)
+			}
+			attributes[key[1:]] = &attrs
+
+		default:
+			var translation string
+			if err := dec.Decode(&translation); err != nil {
+				return flutter.Locale{}, nil, fmt.Errorf("invalid translation value for %s", key)
+			}
+
+			messages = append(messages, &convert.ARBMessage{
+				Name:        key,
+				Translation: translation,
+			})
+		}
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return flutter.Locale{}, nil, fmt.Errorf("failed to decode ARB: %w", err)
+	}
+
+	if lang == "" {
+		return flutter.Locale{}, nil, errors.New("missing locale key")
 	}
 
 	locale, err = flutter.ParseLocale(lang)
 	if err != nil {
-		err = fmt.Errorf("parsing locale %s: %w", lang, err)
-		return flutter.Locale{}, nil, err
+		return flutter.Locale{}, nil, fmt.Errorf("parsing locale %s: %w", lang, err)
 	}
 
-	for key, value := range arb {
-		if strings.HasPrefix(key, "@") {
-			continue
+	for _, message := range messages {
+		if attrs, ok := attributes[message.Name]; ok {
+			message.Attributes = attrs.toMessageAttributes()
 		}
+	}
 
-		var translation string
-		if translation, ok = value.(string); !ok {
-			err = fmt.Errorf("invalid translation value for %s", key)
-			return flutter.Locale{}, nil, err
-		}
+	return locale, messages, nil
+}
 
-		message := &convert.ARBMessage{
-			Name:        key,
-			Translation: translation,
-		}
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
 
-		if attrs, ok := arb["@"+key].(map[string]any); ok {
-			encoded, err := json.Marshal(attrs)
-			if err != nil {
-				return flutter.Locale{}, nil, fmt.Errorf("failed to encode attributes for %s: %w", key, err)
-			}
+	if token != delim {
+		return fmt.Errorf("expected %s, got %v", delim, token)
+	}
 
-			var attributes struct {
-				Placeholders map[string]struct {
-					Type   string `json:"type,omitempty"`
-					Format string `json:"format,omitempty"`
-				} `json:"placeholders,omitempty"`
-			}
-			err = json.Unmarshal(encoded, &attributes)
-			if err != nil {
-				return flutter.Locale{}, nil, fmt.Errorf("failed to decode attributes for %s: %w", key, err)
-			}
+	return nil
+}
 
-			attrsOm := orderedmap.New[string, *convert.ARBPlaceholder]()
-			for placeholderName, placeholder := range attributes.Placeholders {
-				attrsOm.Set(placeholderName, &convert.ARBPlaceholder{
-					Name:   placeholderName,
-					Type:   placeholder.Type,
-					Format: placeholder.Format,
-				})
+func (a *arbAttributes) toMessageAttributes() *convert.ARBMessageAttributes {
+	placeholders := orderedmap.New[string, *convert.ARBPlaceholder]()
+	if a.Placeholders != nil {
+		for pair := a.Placeholders.Oldest(); pair != nil; pair = pair.Next() {
+			name, placeholder := pair.Key, pair.Value
+
+			arbPlaceholder := &convert.ARBPlaceholder{
+				Name:               name,
+				Type:               placeholder.Type,
+				Format:             placeholder.Format,
+				OptionalParameters: placeholder.OptionalParameters,
+				Example:            placeholder.Example,
 			}
 
-			message.Attributes = &convert.ARBMessageAttributes{
-				Placeholders: attrsOm,
+			// Flutter accepts both "true" and true.
+			if isCustom := placeholder.IsCustomDateFormat; isCustom == true || isCustom == "true" {
+				arbPlaceholder.IsCustomDateFormat = "true"
 			}
-		}
 
-		messages = append(messages, message)
+			placeholders.Set(name, arbPlaceholder)
+		}
 	}
 
-	return
+	return &convert.ARBMessageAttributes{
+		Description:  a.Description,
+		Placeholders: placeholders,
+	}
 }
@@ -2,6 +2,7 @@ package arb2poe
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/leancodepl/poe2arb/flutter"
@@ -17,4 +18,59 @@ func TestParseARB(t *testing.T) {
 	assert.Equal(t, flutter.Locale{Language: "en"}, lang)
 	assert.NotEmpty(t, messages)
 	assert.NoError(t, err)
+
+	for _, message := range messages {
+		if message.Name == "simpleTerm" {
+			assert.Equal(t, "Greeting shown on the home screen", message.Attributes.Description)
+		}
+	}
+}
+
+func TestParseARBKeepsOrder(t *testing.T) {
+	file, _ := os.Open("testdata/english.arb")
+	defer file.Close()
+
+	_, messages, err := parseARB(file)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, message := range messages {
+		names = append(names, message.Name)
+	}
+
+	assert.Equal(t, []string{
+		"simpleTerm", "termOnlyInTemplate", "termOnlyInNonTemplate", "pluralNoParams", "pluralCountParam",
+		"pluralComplexParams", "params", "repeatedParams", "redefinedParams",
+	}, names)
+
+	params := messages[6].Attributes.Placeholders
+	var placeholderNames []string
+	for pair := params.Oldest(); pair != nil; pair = pair.Next() {
+		placeholderNames = append(placeholderNames, pair.Key)
+	}
+
+	assert.Equal(t, []string{"simple", "definedDate", "someCount", "percentage"}, placeholderNames)
+}
+
+func TestParseARBErrors(t *testing.T) {
+	type testCase struct {
+		Name          string
+		Input         string
+		ExpectedError string
+	}
+
+	testCases := []testCase{
+		{"not an object", `[]`, "failed to decode ARB: expected {, got ["},
+		{"missing locale", `{"hello": "Hello"}`, "missing locale key"},
+		{"non-string translation", `{"@@locale": "en", "hello": 1}`, "invalid translation value for hello"},
+		{"invalid attributes", `{"@@locale": "en", "@hello": []}`, "failed to decode attributes for hello: json: cannot unmarshal array into Go value of type arb2poe.arbAttributes"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			_, _, err := parseARB(strings.NewReader(testCase.Input))
+
+			assert.EqualError(t, err, testCase.ExpectedError)
+		})
+	}
 }
@@ -8,6 +8,7 @@ import (
 	"io"
 
 	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
 	"github.com/leancodepl/poe2arb/flutter"
 )
 
@@ -16,44 +17,58 @@ type Converter struct {
 
 	templateLocale flutter.Locale
 	termPrefix     string
+	syntax         icu.Options
 }
 
-func NewConverter(input io.Reader, templateLocale flutter.Locale, termPrefix string) *Converter {
+// NewConverter creates a converter. syntax are the options ARB messages are parsed
+// and POEditor translations are written with, e.g. ICU quoting with apostrophes.
+func NewConverter(input io.Reader, templateLocale flutter.Locale, termPrefix string, syntax icu.Options) *Converter {
 	return &Converter{
 		input:          input,
 		templateLocale: templateLocale,
 		termPrefix:     termPrefix,
+		syntax:         syntax,
 	}
 }
 
 var ErrNoTerms = errors.New("no terms to convert")
 
 func (c *Converter) Convert(output io.Writer) (lang flutter.Locale, err error) {
+	lang, poeTerms, err := c.ConvertTerms()
+	if err != nil {
+		return flutter.Locale{}, err
+	}
+
+	err = json.NewEncoder(output).Encode(poeTerms)
+	if err != nil {
+		return flutter.Locale{}, fmt.Errorf("failed to encode POEditor JSON: %w", err)
+	}
+
+	return lang, nil
+}
+
+// ConvertTerms converts the ARB to POEditor terms without encoding them.
+func (c *Converter) ConvertTerms() (lang flutter.Locale, terms []*convert.POETerm, err error) {
 	lang, messages, err := parseARB(c.input)
 	if err != nil {
-		return flutter.Locale{}, fmt.Errorf("failed to parse ARB: %w", err)
+		return flutter.Locale{}, nil, fmt.Errorf("failed to parse ARB: %w", err)
 	}
 
 	template := c.templateLocale == lang
 
 	var poeTerms []*convert.POETerm
 	for _, message := range messages {
-		poeTerm, err := arbMessageToPOETerm(message, !template, c.termPrefix)
+		poeTerm, err := arbMessageToPOETerm(message, !template, c.termPrefix, c.syntax)
 		if err != nil {
-			return flutter.Locale{}, fmt.Errorf("decoding term %q failed: %w", message.Name, err)
+			return flutter.Locale{}, nil, fmt.Errorf("decoding term %q failed: %w", message.Name, err)
 		}
 
 		poeTerms = append(poeTerms, poeTerm)
 	}
 
 	if len(poeTerms) == 0 {
-		return flutter.Locale{}, ErrNoTerms
-	}
-
-	err = json.NewEncoder(output).Encode(poeTerms)
-	if err != nil {
-		return flutter.Locale{}, fmt.Errorf("failed to encode POEditor JSON: %w", err)
+		return flutter.Locale{}, nil, ErrNoTerms
 	}
 
-	return lang, nil
+	return lang, poeTerms, nil
 }
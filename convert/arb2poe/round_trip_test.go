@@ -0,0 +1,69 @@
+package arb2poe_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/arb2poe"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/convert/poe2arb"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoundTrip checks that literal brackets and apostrophes survive
+// converting to ARB and back with use-escaping enabled.
+func TestRoundTrip(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	terms := []*convert.POETerm{
+		{
+			Term:       "braces",
+			Definition: convert.POETermDefinition{Value: ptr("Use {braces,String} like '{this}', it''s easy")},
+		},
+		{
+			Term:       "pronoun",
+			Definition: convert.POETermDefinition{Value: ptr("{gender, select, male {He wrote '{x}'} other {They wrote '{x}'}}")},
+		},
+		{
+			Term:       "items",
+			TermPlural: ".",
+			Definition: convert.POETermDefinition{
+				IsPlural: true,
+				Plural: &convert.POETermPluralDefinition{
+					One:   ptr("One '{item}'"),
+					Other: "{count,int} '{items}'",
+				},
+			},
+		},
+	}
+
+	input, err := json.Marshal(terms)
+	assert.NoError(t, err)
+
+	locale := flutter.Locale{Language: "en"}
+
+	var arb bytes.Buffer
+	err = poe2arb.NewConverter(bytes.NewReader(input), &poe2arb.ConverterOptions{
+		Locale:      locale,
+		Template:    true,
+		UseEscaping: true,
+	}).Convert(&arb)
+	assert.NoError(t, err)
+
+	_, roundTripped, err := arb2poe.NewConverter(&arb, locale, "", icu.Options{Escaping: true, Relaxed: true}).ConvertTerms()
+	assert.NoError(t, err)
+
+	byName := map[string]*convert.POETerm{}
+	for _, term := range roundTripped {
+		byName[term.Term] = term
+	}
+
+	for _, term := range terms {
+		if assert.Contains(t, byName, term.Term) {
+			assert.True(t, term.Definition.Equal(byName[term.Term].Definition), term.Term)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package icu parses and prints ICU MessageFormat messages, as used by Flutter ARB files
+// and POEditor terms.
+package icu
+
+// Message is a sequence of text and arguments.
+type Message []Node
+
+// Node is a part of a message: *Text, *Argument, *Plural or *Select.
+type Node interface {
+	node()
+}
+
+// Text is a literal text, with escapes already resolved.
+type Text struct {
+	Value string
+}
+
+// Argument is a simple argument, e.g. {name}, {name, type} or {name, type, style}.
+type Argument struct {
+	Name  string
+	Type  string
+	Style string
+}
+
+// Plural is a plural argument, e.g. {count, plural, one {1 item} other {{count} items}}.
+type Plural struct {
+	Name  string
+	Cases []*Case
+}
+
+// Select is a select argument, e.g. {gender, select, male {He} other {They}}.
+type Select struct {
+	Name  string
+	Cases []*Case
+}
+
+// Case is a single case of a plural or select argument.
+type Case struct {
+	// Key is a plural category, an exact value (e.g. =1) or a select value.
+	Key     string
+	Message Message
+}
+
+func (*Text) node()     {}
+func (*Argument) node() {}
+func (*Plural) node()   {}
+func (*Select) node()   {}
+
+// Walk calls fn for every node of the message, including nodes of plural and select cases,
+// in the order they appear in the message.
+func Walk(m Message, fn func(Node)) {
+	for _, node := range m {
+		fn(node)
+
+		switch n := node.(type) {
+		case *Plural:
+			for _, c := range n.Cases {
+				Walk(c.Message, fn)
+			}
+		case *Select:
+			for _, c := range n.Cases {
+				Walk(c.Message, fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package icu
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Options configure parsing and printing of messages.
+type Options struct {
+	// Escaping enables quoting with apostrophes, like Flutter's use-escaping option.
+	// Text between apostrophes is literal and two apostrophes make a single one.
+	Escaping bool
+
+	// Relaxed treats brackets which don't start an argument as text, like Flutter's
+	// relax-syntax option. An argument starts with a bracket, a name and a comma or
+	// a closing bracket, e.g. "{}" and "{ hi! }" are text, but "{name, plural" is an error.
+	Relaxed bool
+}
+
+// SyntaxError describes an invalid message.
+type SyntaxError struct {
+	// Offset is the byte offset in the message the error was found at.
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// Parse parses the message. Returned errors are of *SyntaxError type.
+func Parse(message string, options Options) (Message, error) {
+	p := &parser{input: message, options: options}
+
+	m, err := p.parseMessage(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type parser struct {
+	input   string
+	pos     int
+	options Options
+}
+
+// parseMessage parses text and arguments until the end of the input
+// or, for nested messages, until the closing bracket (not consumed).
+func (p *parser) parseMessage(nested bool) (Message, error) {
+	var m Message
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			m = append(m, &Text{Value: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.input) {
+		switch c := p.input[p.pos]; {
+		case c == '{':
+			start := p.pos
+			node, started, err := p.parseArgument()
+			if err != nil {
+				if !p.options.Relaxed || started {
+					return nil, err
+				}
+
+				p.pos = start + 1
+				text.WriteByte('{')
+				continue
+			}
+
+			flushText()
+			m = append(m, node)
+
+		case c == '}':
+			if nested {
+				flushText()
+				return m, nil
+			}
+
+			if !p.options.Relaxed {
+				return nil, p.syntaxError(p.pos, "unexpected }")
+			}
+
+			p.pos++
+			text.WriteByte('}')
+
+		case c == '\'' && p.options.Escaping:
+			quoted, err := p.parseQuoted()
+			if err != nil {
+				return nil, err
+			}
+			text.WriteString(quoted)
+
+		default:
+			_, size := utf8.DecodeRuneInString(p.input[p.pos:])
+			text.WriteString(p.input[p.pos : p.pos+size])
+			p.pos += size
+		}
+	}
+
+	flushText()
+	return m, nil
+}
+
+// parseQuoted parses text in apostrophes, or two apostrophes meaning a single one.
+func (p *parser) parseQuoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening apostrophe
+
+	if strings.HasPrefix(p.input[p.pos:], "'") {
+		p.pos++
+		return "'", nil
+	}
+
+	var sb strings.Builder
+	for p.pos < len(p.input) {
+		if p.input[p.pos] != '\'' {
+			sb.WriteByte(p.input[p.pos])
+			p.pos++
+			continue
+		}
+
+		if strings.HasPrefix(p.input[p.pos:], "''") {
+			sb.WriteByte('\'')
+			p.pos += 2
+			continue
+		}
+
+		p.pos++ // closing apostrophe
+		return sb.String(), nil
+	}
+
+	if !p.options.Relaxed {
+		return "", p.syntaxError(start, "unterminated quoted text")
+	}
+
+	return sb.String(), nil
+}
+
+// parseArgument parses an argument starting at the opening bracket.
+// Started is true if the argument name was followed by a comma, see Options.Relaxed.
+func (p *parser) parseArgument() (node Node, started bool, err error) {
+	start := p.pos
+	p.pos++ // opening bracket
+
+	p.skipSpaces()
+	name := p.parseIdentifier()
+	if name == "" {
+		return nil, false, p.syntaxError(p.pos, "expected argument name")
+	}
+	p.skipSpaces()
+
+	if p.consume('}') {
+		return &Argument{Name: name}, true, nil
+	}
+
+	if !p.consume(',') {
+		return nil, false, p.unterminatedOr(start, "expected , or } after argument name")
+	}
+
+	node, err = p.parseArgumentType(start, name)
+	return node, true, err
+}
+
+// parseArgumentType parses the rest of the argument following the name and a comma.
+func (p *parser) parseArgumentType(start int, name string) (Node, error) {
+	p.skipSpaces()
+	argType := p.parseIdentifier()
+	if argType == "" {
+		return nil, p.syntaxError(p.pos, "expected argument type")
+	}
+	p.skipSpaces()
+
+	if p.consume('}') {
+		return &Argument{Name: name, Type: argType}, nil
+	}
+
+	if !p.consume(',') {
+		return nil, p.unterminatedOr(start, "expected , or } after argument type")
+	}
+
+	switch argType {
+	case "plural":
+		cases, err := p.parseCases(start)
+		if err != nil {
+			return nil, err
+		}
+		return &Plural{Name: name, Cases: cases}, nil
+
+	case "select":
+		cases, err := p.parseCases(start)
+		if err != nil {
+			return nil, err
+		}
+		return &Select{Name: name, Cases: cases}, nil
+	}
+
+	style, err := p.parseStyle(start)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Argument{Name: name, Type: argType, Style: style}, nil
+}
+
+// parseCases parses plural or select cases, up to and including the closing bracket of the argument.
+func (p *parser) parseCases(argStart int) ([]*Case, error) {
+	var cases []*Case
+
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) {
+			return nil, p.syntaxError(argStart, "unterminated argument")
+		}
+
+		if p.consume('}') {
+			return cases, nil
+		}
+
+		keyStart := p.pos
+		for p.pos < len(p.input) && !isSpace(p.input[p.pos]) && p.input[p.pos] != '{' && p.input[p.pos] != '}' {
+			p.pos++
+		}
+		key := p.input[keyStart:p.pos]
+		if key == "" {
+			return nil, p.syntaxError(p.pos, "expected case key")
+		}
+
+		p.skipSpaces()
+		if !p.consume('{') {
+			return nil, p.unterminatedOr(argStart, fmt.Sprintf("expected { after case %s", key))
+		}
+
+		bodyStart := p.pos - 1
+		m, err := p.parseMessage(true)
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume('}') {
+			return nil, p.syntaxError(bodyStart, fmt.Sprintf("unterminated case %s", key))
+		}
+
+		cases = append(cases, &Case{Key: key, Message: m})
+	}
+}
+
+// parseStyle parses the argument style up to and including the closing bracket of the argument.
+// Brackets in the style must be balanced, unless they are in a double-quoted string.
+func (p *parser) parseStyle(argStart int) (string, error) {
+	start := p.pos
+	depth, inQuotes, escaped := 0, false, false
+
+	for ; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && c == '\\':
+			escaped = true
+		case c == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case c == '{':
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case c == '}':
+			style := strings.TrimSpace(p.input[start:p.pos])
+			p.pos++
+			return style, nil
+		}
+	}
+
+	return "", p.syntaxError(argStart, "unterminated argument")
+}
+
+func (p *parser) parseIdentifier() string {
+	start := p.pos
+	for p.pos < len(p.input) && isIdentifierChar(p.input[p.pos], p.pos == start) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isIdentifierChar(c byte, first bool) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9', c == '_':
+		return !first
+	default:
+		return false
+	}
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// unterminatedOr reports an unterminated argument at the end of the input
+// and the given error otherwise.
+func (p *parser) unterminatedOr(argStart int, msg string) error {
+	if p.pos >= len(p.input) {
+		return p.syntaxError(argStart, "unterminated argument")
+	}
+	return p.syntaxError(p.pos, msg)
+}
+
+func (p *parser) syntaxError(offset int, msg string) error {
+	return &SyntaxError{Offset: offset, Msg: msg}
+}
@@ -0,0 +1,166 @@
+package icu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Input    string
+		Options  Options
+		Expected Message
+	}
+
+	cases := []testCase{
+		{
+			Name:     "empty",
+			Input:    "",
+			Expected: nil,
+		},
+		{
+			Name:     "text",
+			Input:    "Hello, world!",
+			Expected: Message{&Text{"Hello, world!"}},
+		},
+		{
+			Name:  "arguments",
+			Input: "Hello, { name }! {date,DateTime,yMd} {price, double, currency(name: \"{EUR}\")}",
+			Expected: Message{
+				&Text{"Hello, "},
+				&Argument{Name: "name"},
+				&Text{"! "},
+				&Argument{Name: "date", Type: "DateTime", Style: "yMd"},
+				&Text{" "},
+				&Argument{Name: "price", Type: "double", Style: `currency(name: "{EUR}")`},
+			},
+		},
+		{
+			Name:  "plural",
+			Input: "{count,plural, =0{none} one {{count} item}other{{count} items}}",
+			Expected: Message{
+				&Plural{Name: "count", Cases: []*Case{
+					{"=0", Message{&Text{"none"}}},
+					{"one", Message{&Argument{Name: "count"}, &Text{" item"}}},
+					{"other", Message{&Argument{Name: "count"}, &Text{" items"}}},
+				}},
+			},
+		},
+		{
+			Name:  "select nested in text",
+			Input: "Ask {gender, select, male {him} other {them}}.",
+			Expected: Message{
+				&Text{"Ask "},
+				&Select{Name: "gender", Cases: []*Case{
+					{"male", Message{&Text{"him"}}},
+					{"other", Message{&Text{"them"}}},
+				}},
+				&Text{"."},
+			},
+		},
+		{
+			Name:  "empty case",
+			Input: "{gender, select, other {}}",
+			Expected: Message{
+				&Select{Name: "gender", Cases: []*Case{{"other", nil}}},
+			},
+		},
+		{
+			Name:     "apostrophes without escaping",
+			Input:    "Don't '{name}'",
+			Expected: Message{&Text{"Don't '"}, &Argument{Name: "name"}, &Text{"'"}},
+		},
+		{
+			Name:     "escaping",
+			Input:    "Don''t escape {name}, but '{this}' and '{that''s}'",
+			Options:  Options{Escaping: true},
+			Expected: Message{&Text{"Don't escape "}, &Argument{Name: "name"}, &Text{", but {this} and {that's}"}},
+		},
+		{
+			Name:     "relaxed",
+			Input:    "a { b } {} {name} }",
+			Options:  Options{Relaxed: true},
+			Expected: Message{&Text{"a "}, &Argument{Name: "b"}, &Text{" {} "}, &Argument{Name: "name"}, &Text{" }"}},
+		},
+		{
+			Name:     "relaxed not started arguments",
+			Input:    "{name {1} {name!} {",
+			Options:  Options{Relaxed: true},
+			Expected: Message{&Text{"{name {1} {name!} {"}},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			m, err := Parse(testCase.Input, testCase.Options)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, m)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	type testCase struct {
+		Input         string
+		Options       Options
+		ExpectedError string
+	}
+
+	cases := []testCase{
+		{"Hello {", Options{}, "expected argument name at offset 7"},
+		{"Hello {name", Options{}, "unterminated argument at offset 6"},
+		{"Hello {name!}", Options{}, "expected , or } after argument name at offset 11"},
+		{"Hello {1name}", Options{}, "expected argument name at offset 7"},
+		{"Hello }", Options{}, "unexpected } at offset 6"},
+		{"{name, }", Options{}, "expected argument type at offset 7"},
+		{"{name, type, style", Options{}, "unterminated argument at offset 0"},
+		{"{count, plural, one {one} other", Options{}, "unterminated argument at offset 0"},
+		{"{count, plural, one one}", Options{}, "expected { after case one at offset 20"},
+		{"{count, plural, one {one", Options{}, "unterminated case one at offset 20"},
+		{"{count, plural, one {{}}}", Options{}, "expected argument name at offset 22"},
+		{"It's", Options{Escaping: true}, "unterminated quoted text at offset 2"},
+		{"{gender, select, male {He}", Options{Relaxed: true}, "unterminated argument at offset 0"},
+		{"{name, }", Options{Relaxed: true}, "expected argument type at offset 7"},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Input, func(t *testing.T) {
+			m, err := Parse(testCase.Input, testCase.Options)
+
+			assert.Nil(t, m)
+			assert.EqualError(t, err, testCase.ExpectedError)
+
+			var syntaxErr *SyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"Hello, {name}!",
+		"{count, plural, =0 {none} one {{count} item} other {{count} items}}",
+		"{gender, select, male {He} other {They}} said {price,double,currency(name: \"EUR\")}",
+		"Don''t '{escape}' {",
+		"}{{a, plural, x {",
+	} {
+		f.Add(seed, false, false)
+		f.Add(seed, true, true)
+	}
+
+	f.Fuzz(func(t *testing.T, input string, escaping, relaxed bool) {
+		options := Options{Escaping: escaping, Relaxed: relaxed}
+
+		m, err := Parse(input, options)
+		if err != nil {
+			return
+		}
+
+		reparsed, err := Parse(Print(m, options), options)
+		assert.NoError(t, err)
+		assert.Equal(t, m, reparsed)
+	})
+}
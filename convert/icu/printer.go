@@ -0,0 +1,80 @@
+package icu
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Print formats the message. Parsing the printed message with the same options
+// gives back the same message, as long as it was returned by Parse.
+//
+// Arguments are printed without spaces, e.g. {name,type,style}, and plurals and selects
+// with single spaces, e.g. {count, plural, one {1 item} other {{count} items}}.
+func Print(m Message, options Options) string {
+	var sb strings.Builder
+	printMessage(&sb, m, options)
+	return sb.String()
+}
+
+func printMessage(sb *strings.Builder, m Message, options Options) {
+	for _, node := range m {
+		switch n := node.(type) {
+		case *Text:
+			printText(sb, n.Value, options)
+
+		case *Argument:
+			sb.WriteString("{" + n.Name)
+			if n.Type != "" {
+				sb.WriteString("," + n.Type)
+			}
+			if n.Style != "" {
+				sb.WriteString("," + n.Style)
+			}
+			sb.WriteByte('}')
+
+		case *Plural:
+			printCases(sb, n.Name, "plural", n.Cases, options)
+
+		case *Select:
+			printCases(sb, n.Name, "select", n.Cases, options)
+		}
+	}
+}
+
+func printCases(sb *strings.Builder, name, kind string, cases []*Case, options Options) {
+	sb.WriteString("{" + name + ", " + kind + ",")
+	for _, c := range cases {
+		sb.WriteString(" " + c.Key + " {")
+		printMessage(sb, c.Message, options)
+		sb.WriteByte('}')
+	}
+	sb.WriteByte('}')
+}
+
+// printText writes the text, quoting apostrophes and brackets if escaping is enabled.
+// Brackets are quoted per word, e.g. '{this}', to keep the text readable.
+func printText(sb *strings.Builder, text string, options Options) {
+	if !options.Escaping {
+		sb.WriteString(text)
+		return
+	}
+
+	for text != "" {
+		end := strings.IndexFunc(text, unicode.IsSpace)
+		if end == 0 {
+			_, end = utf8.DecodeRuneInString(text)
+		} else if end == -1 {
+			end = len(text)
+		}
+
+		word := strings.ReplaceAll(text[:end], "'", "''")
+		if first := strings.IndexAny(word, "{}"); first != -1 {
+			last := strings.LastIndexAny(word, "{}") + 1
+			word = word[:first] + "'" + word[first:last] + "'" + word[last:]
+		}
+
+		sb.WriteString(word)
+		text = text[end:]
+	}
+}
@@ -0,0 +1,73 @@
+package icu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintRoundTrip(t *testing.T) {
+	type testCase struct {
+		Input   string
+		Options Options
+	}
+
+	cases := []testCase{
+		{"Hello, world!", Options{}},
+		{"Hello, {name}! {date,DateTime,yMd} {price,double,currency(name: \"EUR\")}", Options{}},
+		{"{count, plural, =0 {none} one {{count} item} other {{count} items}}", Options{}},
+		{"Ask {gender, select, male {him} other {them}}.", Options{}},
+		{"{gender, select, other {}}", Options{}},
+		{"Don't '{name}'", Options{}},
+		{"Don''t escape {name}, but '{this}' and '{that''s}'", Options{Escaping: true}},
+		{"a {b} { {name} }", Options{Relaxed: true}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Input, func(t *testing.T) {
+			m, err := Parse(testCase.Input, testCase.Options)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.Input, Print(m, testCase.Options))
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	type testCase struct {
+		Name     string
+		Message  Message
+		Options  Options
+		Expected string
+	}
+
+	cases := []testCase{
+		{
+			Name:     "normalizes spaces",
+			Message:  mustParse(t, "{ name , String }{count,plural,one{one}other  {other}}", Options{}),
+			Expected: "{name,String}{count, plural, one {one} other {other}}",
+		},
+		{
+			Name:     "escapes text",
+			Message:  Message{&Text{"It's {not} a {placeholder}, ({really})"}},
+			Options:  Options{Escaping: true},
+			Expected: "It''s '{not}' a '{placeholder}', ('{really}')",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			assert.Equal(t, testCase.Expected, Print(testCase.Message, testCase.Options))
+		})
+	}
+}
+
+func mustParse(t *testing.T, input string, options Options) Message {
+	t.Helper()
+
+	m, err := Parse(input, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
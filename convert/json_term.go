@@ -4,14 +4,39 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 )
 
+const (
+	messageParameterPattern = `[a-zA-Z][a-zA-Z_\d]*`
+
+	// DefaultCountPlaceholderName is the placeholder driving plural terms
+	// without a custom one, see POETerm.CountPlaceholderName.
+	DefaultCountPlaceholderName = "count"
+)
+
+var countPlaceholderNameRegexp = regexp.MustCompile(`^{(` + messageParameterPattern + `)}$`)
+
 type POETerm struct {
 	Term       string            `json:"term"`
 	TermPlural string            `json:"term_plural"`
+	Context    string            `json:"context,omitempty"`
+	Comment    string            `json:"comment,omitempty"`
 	Definition POETermDefinition `json:"definition"`
 }
 
+// CountPlaceholderName returns the name of the placeholder driving the plural term.
+// It can be customized by setting the term's plural name to a placeholder,
+// e.g. {itemCount}. Defaults to DefaultCountPlaceholderName.
+func (t *POETerm) CountPlaceholderName() string {
+	if matches := countPlaceholderNameRegexp.FindStringSubmatch(t.TermPlural); matches != nil {
+		return matches[1]
+	}
+
+	return DefaultCountPlaceholderName
+}
+
 type POETermDefinition struct {
 	IsPlural bool
 
@@ -49,6 +74,40 @@ func (d POETermDefinition) MarshalJSON() ([]byte, error) {
 	return json.Marshal(d.Value)
 }
 
+// Equal reports whether both definitions have the same translations.
+// Missing and empty translations are considered equal.
+func (d POETermDefinition) Equal(other POETermDefinition) bool {
+	if d.IsPlural != other.IsPlural {
+		return false
+	}
+
+	if !d.IsPlural {
+		return stringOrEmpty(d.Value) == stringOrEmpty(other.Value)
+	}
+
+	a, b := d.Plural, other.Plural
+	if a == nil {
+		a = &POETermPluralDefinition{}
+	}
+	if b == nil {
+		b = &POETermPluralDefinition{}
+	}
+
+	return stringOrEmpty(a.Zero) == stringOrEmpty(b.Zero) &&
+		stringOrEmpty(a.One) == stringOrEmpty(b.One) &&
+		stringOrEmpty(a.Two) == stringOrEmpty(b.Two) &&
+		stringOrEmpty(a.Few) == stringOrEmpty(b.Few) &&
+		stringOrEmpty(a.Many) == stringOrEmpty(b.Many) &&
+		a.Other == b.Other
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
 type POETermPluralDefinition struct {
 	Zero  *string `json:"zero,omitempty"`
 	One   *string `json:"one,omitempty"`
@@ -109,22 +168,31 @@ func (p POETermPluralDefinition) Map(mapper func(string) (string, error)) (*POET
 	}, nil
 }
 
-func (p POETermPluralDefinition) ToICUMessageFormat() string {
-	str := "{count, plural,"
-	if p.Zero != nil {
-		str += fmt.Sprintf(" =0 {%s}", *p.Zero)
-	}
-	if p.One != nil {
-		str += fmt.Sprintf(" =1 {%s}", *p.One)
-	}
-	if p.Two != nil {
-		str += fmt.Sprintf(" =2 {%s}", *p.Two)
-	}
-	if p.Few != nil {
-		str += fmt.Sprintf(" few {%s}", *p.Few)
-	}
-	if p.Many != nil {
-		str += fmt.Sprintf(" many {%s}", *p.Many)
+// ToICUMessageFormat builds a plural message with the given count placeholder.
+// Forms are written as keywords if the locale has such plural category, e.g. one in English.
+// Otherwise zero, one and two are exact matches, e.g. =0 in English.
+func (p POETermPluralDefinition) ToICUMessageFormat(countPlaceholder string, categories []string) string {
+	str := "{" + countPlaceholder + ", plural,"
+	for _, form := range []struct {
+		category string
+		exact    string
+		value    *string
+	}{
+		{"zero", "=0", p.Zero},
+		{"one", "=1", p.One},
+		{"two", "=2", p.Two},
+		{"few", "few", p.Few},
+		{"many", "many", p.Many},
+	} {
+		if form.value == nil {
+			continue
+		}
+
+		key := form.exact
+		if slices.Contains(categories, form.category) {
+			key = form.category
+		}
+		str += fmt.Sprintf(" %s {%s}", key, *form.value)
 	}
 	str += fmt.Sprintf(" other {%s}", p.Other)
 	str += "}"
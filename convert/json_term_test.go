@@ -84,35 +84,125 @@ func TestJSONTermPluralDefinitionToICUMessageFormat(t *testing.T) {
 	type testCase struct {
 		Name           string
 		Input          POETermPluralDefinition
+		Categories     []string
 		ExpectedOutput string
 	}
 
+	all := POETermPluralDefinition{
+		Zero: ptr("zero"), One: ptr("one"),
+		Two: ptr("two"), Few: ptr("few"),
+		Many: ptr("many"), Other: "other",
+	}
+
 	cases := []testCase{
 		{
 			"only other",
 			POETermPluralDefinition{Other: "test"},
+			[]string{"one", "other"},
 			"{count, plural, other {test}}",
 		},
 		{
 			"one and other",
 			POETermPluralDefinition{One: ptr("foobar"), Other: "baz"},
+			[]string{"one", "other"},
+			"{count, plural, one {foobar} other {baz}}",
+		},
+		{
+			"one without one category",
+			POETermPluralDefinition{One: ptr("foobar"), Other: "baz"},
+			[]string{"other"},
 			"{count, plural, =1 {foobar} other {baz}}",
 		},
 		{
-			"all",
-			POETermPluralDefinition{
-				Zero: ptr("zero"), One: ptr("one"),
-				Two: ptr("two"), Few: ptr("few"),
-				Many: ptr("many"), Other: "other",
-			},
-			"{count, plural, =0 {zero} =1 {one} =2 {two} few {few} many {many} other {other}}",
+			"all with all categories",
+			all,
+			[]string{"zero", "one", "two", "few", "many", "other"},
+			"{count, plural, zero {zero} one {one} two {two} few {few} many {many} other {other}}",
+		},
+		{
+			"all with some categories",
+			all,
+			[]string{"one", "few", "many", "other"},
+			"{count, plural, =0 {zero} one {one} =2 {two} few {few} many {many} other {other}}",
 		},
 	}
 
 	for _, testCase := range cases {
 		t.Run(testCase.Name, func(t *testing.T) {
-			output := testCase.Input.ToICUMessageFormat()
+			output := testCase.Input.ToICUMessageFormat("count", testCase.Categories)
 			assert.Equal(t, testCase.ExpectedOutput, output)
 		})
 	}
+
+	t.Run("custom count placeholder", func(t *testing.T) {
+		output := POETermPluralDefinition{Other: "test"}.ToICUMessageFormat("itemCount", nil)
+		assert.Equal(t, "{itemCount, plural, other {test}}", output)
+	})
+}
+
+func TestPOETermDefinitionEqual(t *testing.T) {
+	type testCase struct {
+		Name     string
+		A        POETermDefinition
+		B        POETermDefinition
+		Expected bool
+	}
+
+	cases := []testCase{
+		{
+			"same values",
+			POETermDefinition{Value: ptr("foo")},
+			POETermDefinition{Value: ptr("foo")},
+			true,
+		},
+		{
+			"different values",
+			POETermDefinition{Value: ptr("foo")},
+			POETermDefinition{Value: ptr("bar")},
+			false,
+		},
+		{
+			"nil and empty value",
+			POETermDefinition{},
+			POETermDefinition{Value: ptr("")},
+			true,
+		},
+		{
+			"plural and non-plural",
+			POETermDefinition{Value: ptr("foo")},
+			POETermDefinition{IsPlural: true, Plural: &POETermPluralDefinition{Other: "foo"}},
+			false,
+		},
+		{
+			"plurals with missing and empty categories",
+			POETermDefinition{IsPlural: true, Plural: &POETermPluralDefinition{One: ptr("one"), Other: "other"}},
+			POETermDefinition{IsPlural: true, Plural: &POETermPluralDefinition{One: ptr("one"), Few: ptr(""), Other: "other"}},
+			true,
+		},
+		{
+			"different plurals",
+			POETermDefinition{IsPlural: true, Plural: &POETermPluralDefinition{One: ptr("one"), Other: "other"}},
+			POETermDefinition{IsPlural: true, Plural: &POETermPluralDefinition{One: ptr("uno"), Other: "other"}},
+			false,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			assert.Equal(t, testCase.Expected, testCase.A.Equal(testCase.B))
+			assert.Equal(t, testCase.Expected, testCase.B.Equal(testCase.A))
+		})
+	}
+}
+
+func TestPOETermCountPlaceholderName(t *testing.T) {
+	for termPlural, expected := range map[string]string{
+		"":            "count",
+		"plural":      "count",
+		"{1invalid}":  "count",
+		"{itemCount}": "itemCount",
+	} {
+		term := POETerm{TermPlural: termPlural}
+		assert.Equal(t, expected, term.CountPlaceholderName(), termPlural)
+	}
 }
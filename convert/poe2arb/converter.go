@@ -12,10 +12,14 @@ import (
 
 	"facette.io/natsort"
 	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
 	"github.com/leancodepl/poe2arb/flutter"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
+// prefixedTermRegexp matches term names with an optional term prefix.
+var prefixedTermRegexp = regexp.MustCompile("(?:([a-zA-Z]+):)?(.*)")
+
 type Converter struct {
 	input io.Reader
 
@@ -23,6 +27,19 @@ type Converter struct {
 	template                  bool
 	requireResourceAttributes bool
 	termPrefix                string
+	overridePrefixes          []string
+	naming                    NamingStrategy
+	useEscaping               bool
+	strictSyntax              bool
+	templatePlaceholders      map[string][]string
+	placeholderMismatch       PlaceholderMismatchMode
+	incompletePlurals         IncompletePluralMode
+	pluralCategories          []string
+
+	placeholders map[string][]string
+	mismatches   []*PlaceholderMismatch
+	incomplete   []*IncompletePlural
+	coverage     Coverage
 }
 
 type ConverterOptions struct {
@@ -30,6 +47,29 @@ type ConverterOptions struct {
 	Template                  bool
 	RequireResourceAttributes bool
 	TermPrefix                string
+	// OverridePrefixes are term prefixes layered on top of TermPrefix, each overriding
+	// terms of the previous ones with the same name. Empty translations don't override.
+	OverridePrefixes []string
+	// Naming decides how term names are turned into message names. Defaults to NamingKeep.
+	Naming NamingStrategy
+	// UseEscaping enables ICU quoting with apostrophes, the same as gen-l10n use-escaping option.
+	UseEscaping bool
+
+	// StrictSyntax reports brackets which don't start a placeholder as syntax errors
+	// and fails on syntax errors in non-template translations. By default, such brackets
+	// are kept as text and invalid non-template translations are copied verbatim.
+	StrictSyntax bool
+
+	// TemplatePlaceholders are placeholder names of the template messages, see Converter.Placeholders.
+	// When set, placeholders of non-template messages are validated against them.
+	TemplatePlaceholders map[string][]string
+	// PlaceholderMismatch decides what happens to messages that failed the validation.
+	// Defaults to PlaceholderMismatchWarn.
+	PlaceholderMismatch PlaceholderMismatchMode
+
+	// IncompletePlurals decides what happens to plural messages missing categories
+	// required by the language. Defaults to IncompletePluralWarn.
+	IncompletePlurals IncompletePluralMode
 }
 
 func NewConverter(
@@ -43,9 +83,42 @@ func NewConverter(
 		template:                  options.Template,
 		requireResourceAttributes: options.RequireResourceAttributes,
 		termPrefix:                options.TermPrefix,
+		overridePrefixes:          options.OverridePrefixes,
+		naming:                    options.Naming,
+		useEscaping:               options.UseEscaping,
+		strictSyntax:              options.StrictSyntax,
+		templatePlaceholders:      options.TemplatePlaceholders,
+		placeholderMismatch:       options.PlaceholderMismatch,
+		incompletePlurals:         options.IncompletePlurals,
+		pluralCategories:          options.Locale.PluralCategories(),
+
+		placeholders: map[string][]string{},
 	}
 }
 
+// Placeholders returns names of the placeholders used by every converted message.
+// Available after Convert.
+func (c *Converter) Placeholders() map[string][]string {
+	return c.placeholders
+}
+
+// PlaceholderMismatches returns non-template messages whose placeholders
+// differ from the template ones. Available after Convert.
+func (c *Converter) PlaceholderMismatches() []*PlaceholderMismatch {
+	return c.mismatches
+}
+
+// IncompletePlurals returns plural messages missing categories required by the language.
+// Available after Convert.
+func (c *Converter) IncompletePlurals() []*IncompletePlural {
+	return c.incomplete
+}
+
+// Coverage returns how many of the messages are translated. Available after Convert.
+func (c *Converter) Coverage() *Coverage {
+	return &c.coverage
+}
+
 func (c *Converter) Convert(output io.Writer) error {
 	var jsonContents []*convert.POETerm
 	err := json.NewDecoder(c.input).Decode(&jsonContents)
@@ -56,13 +129,13 @@ func (c *Converter) Convert(output io.Writer) error {
 	arb := orderedmap.New[string, any]()
 	arb.Set(convert.LocaleKey, c.locale.String())
 
-	prefixedRegexp := regexp.MustCompile("(?:([a-zA-Z]+):)?(.*)")
-	var errs []error
+	// Terms by their message names, to detect terms which would overwrite each other.
+	termsByName := orderedmap.New[string, []string]()
 
 	// Sort terms by key alphabetically
 	slices.SortStableFunc(jsonContents, func(a, b *convert.POETerm) int {
-		aKey := prefixedRegexp.FindStringSubmatch(a.Term)[2]
-		bKey := prefixedRegexp.FindStringSubmatch(b.Term)[2]
+		aKey := prefixedTermRegexp.FindStringSubmatch(a.Term)[2]
+		bKey := prefixedTermRegexp.FindStringSubmatch(b.Term)[2]
 
 		if aKey == bKey {
 			return 0
@@ -73,32 +146,74 @@ func (c *Converter) Convert(output io.Writer) error {
 		}
 	})
 
-	for _, term := range jsonContents {
-		// Filter by term prefix
-		matches := prefixedRegexp.FindStringSubmatch(term.Term)
-		if matches[1] == c.termPrefix {
-			term.Term = matches[2]
-		} else {
-			continue
-		}
+	// Filter by term prefix, applying overrides
+	terms, errs := c.applyOverrides(jsonContents)
 
-		message, err := c.parseTerm(term)
+	for _, term := range terms {
+		message, usedPlaceholders, err := c.parseTerm(term)
 		if err != nil {
 			err = fmt.Errorf(`decoding term "%s" failed: %w`, term.Term, err)
 			errs = append(errs, err)
 			continue
 		}
 
+		var name string
+		if message != nil {
+			name = message.Name
+		} else {
+			// plural with no "other" category in a non-template language
+			name, _ = MessageName(term.Term, c.naming)
+		}
+
+		terms, _ := termsByName.Get(name)
+		termsByName.Set(name, append(terms, term.Term))
+
+		c.coverage.Total++
+
 		if message == nil {
+			c.coverage.Missing = append(c.coverage.Missing, name)
 			continue
 		}
 
+		if message.Translation == "" {
+			c.coverage.Missing = append(c.coverage.Missing, message.Name)
+		}
+
 		if !c.template && message.Translation == "" {
 			// Don't generate terms for empty translations if we're not generating a template
 			// https://github.com/leancodepl/poe2arb/issues/42
 			continue
 		}
 
+		c.placeholders[message.Name] = usedPlaceholders
+
+		if !c.template && c.templatePlaceholders != nil {
+			templatePlaceholders, ok := c.templatePlaceholders[message.Name]
+			if mismatch := comparePlaceholders(message.Name, templatePlaceholders, usedPlaceholders); ok && mismatch != nil {
+				c.mismatches = append(c.mismatches, mismatch)
+
+				switch c.placeholderMismatch {
+				case PlaceholderMismatchSkip:
+					continue
+				case PlaceholderMismatchFail:
+					errs = append(errs, mismatch)
+					continue
+				}
+			}
+		}
+
+		if incomplete := c.checkPluralCategories(term, message); incomplete != nil {
+			c.incomplete = append(c.incomplete, incomplete)
+
+			switch c.incompletePlurals {
+			case IncompletePluralSkip:
+				continue
+			case IncompletePluralFail:
+				errs = append(errs, incomplete)
+				continue
+			}
+		}
+
 		arb.Set(message.Name, message.Translation)
 
 		if c.template &&
@@ -108,6 +223,12 @@ func (c *Converter) Convert(output io.Writer) error {
 		}
 	}
 
+	for pair := termsByName.Oldest(); pair != nil; pair = pair.Next() {
+		if len(pair.Value) > 1 {
+			errs = append(errs, &NameCollision{Message: pair.Key, Terms: pair.Value})
+		}
+	}
+
 	if len(errs) > 0 {
 		return errorsToError(errs)
 	}
@@ -124,6 +245,22 @@ func (c *Converter) Convert(output io.Writer) error {
 	}
 }
 
+// checkPluralCategories checks the categories of the plural term, or of the plurals
+// inside the message of a text term.
+func (c Converter) checkPluralCategories(term *convert.POETerm, message *convert.ARBMessage) *IncompletePlural {
+	if term.Definition.IsPlural {
+		return checkPluralCategories(message.Name, term.Definition.Plural, c.pluralCategories)
+	}
+
+	m, err := icu.Parse(message.Translation, icu.Options{Escaping: c.useEscaping, Relaxed: !c.strictSyntax})
+	if err != nil {
+		// invalid translations copied verbatim have no plurals to check
+		return nil
+	}
+
+	return checkMessagePluralCategories(message.Name, m, c.pluralCategories)
+}
+
 func errorsToError(errs []error) error {
 	if len(errs) == 0 {
 		return nil
@@ -140,20 +277,28 @@ func errorsToError(errs []error) error {
 	return errors.New(sb.String())
 }
 
-func (c Converter) parseTerm(term *convert.POETerm) (*convert.ARBMessage, error) {
+// parseTerm returns the message along with names of the placeholders used in it.
+func (c Converter) parseTerm(term *convert.POETerm) (*convert.ARBMessage, []string, error) {
 	var value string
-	tp := newTranslationParser(term.Definition.IsPlural)
 
-	name, err := parseName(term.Term)
+	var countName string
+	if term.Definition.IsPlural {
+		countName = term.CountPlaceholderName()
+	}
+	tp := newPluralTranslationParser(countName)
+	tp.escaping = c.useEscaping
+	tp.strict = c.strictSyntax
+
+	name, err := MessageName(term.Term, c.naming)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !term.Definition.IsPlural {
 		var err error
 		value, err = c.parseSingleTranslation(tp, *term.Definition.Value)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	} else {
 		plural, err := term.Definition.Plural.Map(func(s string) (string, error) {
@@ -161,33 +306,37 @@ func (c Converter) parseTerm(term *convert.POETerm) (*convert.ARBMessage, error)
 			return s, err
 		})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if plural.Other == "" {
 			if c.template {
-				return nil, errors.New(`missing "other" plural category`)
+				return nil, nil, errors.New(`missing "other" plural category`)
 			} else {
-				return nil, nil
+				return nil, nil, nil
 			}
 		}
 
-		value = plural.ToICUMessageFormat()
+		value = plural.ToICUMessageFormat(countName, c.pluralCategories)
 	}
 
+	attributes := tp.BuildMessageAttributes()
+	// POEditor term comment gives translators context, the same as ARB description.
+	attributes.Description = term.Comment
+
 	message := &convert.ARBMessage{
 		Name:        name,
 		Translation: value,
-		Attributes:  tp.BuildMessageAttributes(),
+		Attributes:  attributes,
 	}
 
-	return message, nil
+	return message, tp.UsedPlaceholders(), nil
 }
 
 func (c Converter) parseSingleTranslation(tp *translationParser, translation string) (string, error) {
 	if c.template {
 		return tp.Parse(translation)
 	} else {
-		return tp.ParseDummy(translation), nil
+		return tp.ParseDummy(translation)
 	}
 }
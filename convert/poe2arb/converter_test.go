@@ -32,6 +32,8 @@ func TestConverterConvert(t *testing.T) {
 			}
 			template := !strings.Contains(testname, "-no-template")
 			requireResourceAttributes := strings.Contains(testname, "-req-attrs")
+			useEscaping := strings.Contains(testname, "-escaping")
+			strictSyntax := strings.Contains(testname, "-strict")
 
 			var termPrefix string
 			if strings.Contains(testname, "-prefix") {
@@ -47,7 +49,7 @@ func TestConverterConvert(t *testing.T) {
 			expect := string(golden)
 
 			// Actual test
-			actual, err := convert(string(source), template, requireResourceAttributes, termPrefix)
+			actual, err := convert(string(source), template, requireResourceAttributes, termPrefix, useEscaping, strictSyntax)
 
 			assert.NoError(t, err)
 			assert.Equal(t, expect, actual)
@@ -73,7 +75,7 @@ func TestConverterConvert(t *testing.T) {
 `
 
 	t.Run("issue 41 template", func(t *testing.T) {
-		actual, err := convert(issue41Source, true, false, "")
+		actual, err := convert(issue41Source, true, false, "", false, false)
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, `decoding term "testPlural" failed: missing "other" plural category`)
@@ -81,7 +83,7 @@ func TestConverterConvert(t *testing.T) {
 	})
 
 	t.Run("issue 41 non-template", func(t *testing.T) {
-		actual, err := convert(issue41Source, false, false, "")
+		actual, err := convert(issue41Source, false, false, "", false, false)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "{\n    \"@@locale\": \"en\"\n}\n", actual)
@@ -101,6 +103,8 @@ func convert(
 	template bool,
 	requireResourceAttributes bool,
 	termPrefix string,
+	useEscaping bool,
+	strictSyntax bool,
 ) (converted string, err error) {
 	reader := strings.NewReader(input)
 	conv := poe2arb.NewConverter(reader, &poe2arb.ConverterOptions{
@@ -108,6 +112,8 @@ func convert(
 		Template:                  template,
 		RequireResourceAttributes: requireResourceAttributes,
 		TermPrefix:                termPrefix,
+		UseEscaping:               useEscaping,
+		StrictSyntax:              strictSyntax,
 	})
 	out := new(bytes.Buffer)
 	err = conv.Convert(out)
@@ -116,3 +122,294 @@ func convert(
 
 	return
 }
+
+func TestConverterStrictSyntax(t *testing.T) {
+	source := `[{"term": "text", "definition": "This is {}.", "term_plural": ""}]`
+
+	_, err := convert(source, true, false, "", false, true)
+	assert.ErrorContains(t, err, "invalid message")
+
+	// Syntax errors are reported for translations too.
+	invalidSource := `[{"term": "text", "definition": "{gender, select, male {He}", "term_plural": ""}]`
+	_, err = convert(invalidSource, false, false, "", false, true)
+	assert.ErrorContains(t, err, `decoding term "text" failed: invalid message`)
+
+	// Without strict syntax, invalid translations are copied verbatim.
+	actual, err := convert(invalidSource, false, false, "", false, false)
+	assert.NoError(t, err)
+	assert.Contains(t, actual, `"text": "{gender, select, male {He}"`)
+
+	// Translations with valid syntax have their placeholders not validated.
+	actual, err = convert(`[{"term": "text", "definition": "{count,int} and {count,String}", "term_plural": ""}]`,
+		false, false, "", false, true)
+	assert.NoError(t, err)
+	assert.Contains(t, actual, `"text": "{count} and {count}"`)
+}
+
+func TestConverterPlaceholderMismatch(t *testing.T) {
+	templateSource := `[
+		{"term": "greeting", "definition": "Hello, {userName}!", "term_plural": ""},
+		{"term": "apples", "definition": {"one": "One apple", "other": "{count} apples"}, "term_plural": "."},
+		{"term": "pronoun", "definition": "{gender,select,male{He} other{They}}", "term_plural": ""}
+	]`
+
+	translationSource := `[
+		{"term": "greeting", "definition": "Cześć, {usrName}!", "term_plural": ""},
+		{"term": "apples", "definition": {"one": "Jedno jabłko", "few": "{count} jabłka", "other": "{count} jabłek"}, "term_plural": "."},
+		{"term": "pronoun", "definition": "{gender,select,male{On} other{Oni}}", "term_plural": ""}
+	]`
+
+	templateConv := poe2arb.NewConverter(strings.NewReader(templateSource), &poe2arb.ConverterOptions{
+		Locale:   flutterMustParseLocale("en"),
+		Template: true,
+	})
+	assert.NoError(t, templateConv.Convert(new(bytes.Buffer)))
+	assert.Equal(t, map[string][]string{
+		"greeting": {"userName"},
+		"apples":   {"count"},
+		"pronoun":  {"gender"},
+	}, templateConv.Placeholders())
+
+	convertTranslation := func(mode poe2arb.PlaceholderMismatchMode) (*poe2arb.Converter, string, error) {
+		conv := poe2arb.NewConverter(strings.NewReader(translationSource), &poe2arb.ConverterOptions{
+			Locale:               flutterMustParseLocale("pl"),
+			TemplatePlaceholders: templateConv.Placeholders(),
+			PlaceholderMismatch:  mode,
+		})
+		out := new(bytes.Buffer)
+		err := conv.Convert(out)
+		return conv, out.String(), err
+	}
+
+	expectedMismatches := []*poe2arb.PlaceholderMismatch{
+		{Message: "greeting", Missing: []string{"userName"}, Unknown: []string{"usrName"}},
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		conv, out, err := convertTranslation(poe2arb.PlaceholderMismatchWarn)
+
+		assert.NoError(t, err)
+		assert.Contains(t, out, `"greeting"`)
+		assert.Equal(t, expectedMismatches, conv.PlaceholderMismatches())
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		conv, out, err := convertTranslation(poe2arb.PlaceholderMismatchSkip)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, out, `"greeting"`)
+		assert.Contains(t, out, `"apples"`)
+		assert.Equal(t, expectedMismatches, conv.PlaceholderMismatches())
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		_, _, err := convertTranslation(poe2arb.PlaceholderMismatchFail)
+
+		assert.EqualError(t, err, "greeting: placeholders differ from template: missing userName; unknown usrName")
+	})
+}
+
+func TestConverterPluralCategories(t *testing.T) {
+	source := `[
+		{"term": "items", "definition": {"zero": "No items", "one": "{count} item", "few": "{count} items", "many": "{count} items", "other": "{count} items"}, "term_plural": "."}
+	]`
+
+	testCases := []struct {
+		Locale   string
+		Expected string
+	}{
+		{"en", `{count, plural, =0 {No items} one {{count} item} few {{count} items} many {{count} items} other {{count} items}}`},
+		{"ru", `{count, plural, =0 {No items} one {{count} item} few {{count} items} many {{count} items} other {{count} items}}`},
+		{"lv", `{count, plural, zero {No items} one {{count} item} few {{count} items} many {{count} items} other {{count} items}}`},
+		{"ja", `{count, plural, =0 {No items} =1 {{count} item} few {{count} items} many {{count} items} other {{count} items}}`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Locale, func(t *testing.T) {
+			conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+				Locale:   flutterMustParseLocale(testCase.Locale),
+				Template: true,
+			})
+			out := new(bytes.Buffer)
+
+			assert.NoError(t, conv.Convert(out))
+			assert.Contains(t, out.String(), `"items": "`+testCase.Expected+`"`)
+		})
+	}
+}
+
+func TestConverterIncompletePlurals(t *testing.T) {
+	source := `[
+		{"term": "apples", "definition": {"one": "Jedno jabłko", "other": "{count} jabłek"}, "term_plural": "."},
+		{"term": "pears", "definition": {"one": "Jedna gruszka", "few": "{count} gruszki", "many": "", "other": "{count} gruszek"}, "term_plural": "."},
+		{"term": "plums", "definition": {"one": "Jedna śliwka", "few": "{count} śliwki", "many": "{count} śliwek", "other": "{count} śliwki"}, "term_plural": "."},
+		{"term": "cherries", "definition": "Masz {count, plural, one {jedną wiśnię} other {{count} wiśni}}.", "term_plural": ""}
+	]`
+
+	convertPolish := func(mode poe2arb.IncompletePluralMode) (*poe2arb.Converter, string, error) {
+		conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+			Locale:            flutterMustParseLocale("pl"),
+			IncompletePlurals: mode,
+		})
+		out := new(bytes.Buffer)
+		err := conv.Convert(out)
+		return conv, out.String(), err
+	}
+
+	expectedIncomplete := []*poe2arb.IncompletePlural{
+		{Message: "apples", Missing: []string{"few", "many"}},
+		{Message: "cherries", Missing: []string{"few", "many"}},
+		{Message: "pears", Missing: []string{"many"}},
+	}
+
+	t.Run("warn", func(t *testing.T) {
+		conv, out, err := convertPolish(poe2arb.IncompletePluralWarn)
+
+		assert.NoError(t, err)
+		assert.Contains(t, out, `"apples"`)
+		assert.Contains(t, out, `"pears"`)
+		assert.Equal(t, expectedIncomplete, conv.IncompletePlurals())
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		conv, out, err := convertPolish(poe2arb.IncompletePluralSkip)
+
+		assert.NoError(t, err)
+		assert.NotContains(t, out, `"apples"`)
+		assert.NotContains(t, out, `"pears"`)
+		assert.NotContains(t, out, `"cherries"`)
+		assert.Contains(t, out, `"plums"`)
+		assert.Equal(t, expectedIncomplete, conv.IncompletePlurals())
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		_, _, err := convertPolish(poe2arb.IncompletePluralFail)
+
+		assert.EqualError(t, err, "apples: missing plural categories: few, many\ncherries: missing plural categories: few, many\n"+
+			"pears: missing plural categories: many")
+	})
+
+	t.Run("complete in English", func(t *testing.T) {
+		conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+			Locale:            flutterMustParseLocale("en"),
+			IncompletePlurals: poe2arb.IncompletePluralFail,
+		})
+
+		assert.NoError(t, conv.Convert(new(bytes.Buffer)))
+		assert.Empty(t, conv.IncompletePlurals())
+	})
+}
+
+func TestConverterCoverage(t *testing.T) {
+	source := `[
+		{"term": "greeting", "definition": "Cześć!", "term_plural": ""},
+		{"term": "farewell", "definition": "", "term_plural": ""},
+		{"term": "title", "definition": null, "term_plural": ""},
+		{"term": "apples", "definition": {"one": "", "few": "", "many": "", "other": ""}, "term_plural": "."},
+		{"term": "other:pears", "definition": "", "term_plural": ""}
+	]`
+
+	conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+		Locale: flutterMustParseLocale("pl"),
+	})
+	assert.NoError(t, conv.Convert(new(bytes.Buffer)))
+
+	coverage := conv.Coverage()
+	assert.Equal(t, 4, coverage.Total)
+	assert.Equal(t, []string{"apples", "farewell", "title"}, coverage.Missing)
+	assert.Equal(t, 25.0, coverage.Percentage())
+
+	assert.Equal(t, 100.0, (&poe2arb.Coverage{}).Percentage())
+}
+
+func TestConverterNameCollisions(t *testing.T) {
+	source := `[
+		{"term": "Login.title", "definition": "Log in", "term_plural": ""},
+		{"term": "login_title", "definition": "Log in!", "term_plural": ""},
+		{"term": "loginTitle", "definition": "Sign in", "term_plural": ""}
+	]`
+
+	convertWith := func(naming poe2arb.NamingStrategy) error {
+		conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+			Locale:   flutterMustParseLocale("en"),
+			Template: true,
+			Naming:   naming,
+		})
+		return conv.Convert(new(bytes.Buffer))
+	}
+
+	err := convertWith(poe2arb.NamingKeep)
+	assert.EqualError(t, err, "login_title: terms Login.title, login_title have the same message name")
+
+	err = convertWith(poe2arb.NamingDotToCamel)
+	assert.EqualError(t, err, "loginTitle: terms Login.title, loginTitle have the same message name")
+
+	err = convertWith(poe2arb.NamingSnakeToCamel)
+	assert.EqualError(t, err, "loginTitle: terms Login.title, loginTitle, login_title have the same message name")
+}
+
+func TestConverterOverridePrefixes(t *testing.T) {
+	convertWith := func(source string, template bool, overridePrefixes ...string) (string, error) {
+		conv := poe2arb.NewConverter(strings.NewReader(source), &poe2arb.ConverterOptions{
+			Locale:           flutterMustParseLocale("en"),
+			Template:         template,
+			TermPrefix:       "base",
+			OverridePrefixes: overridePrefixes,
+		})
+		var b bytes.Buffer
+		err := conv.Convert(&b)
+		return b.String(), err
+	}
+
+	t.Run("overrides are applied per term", func(t *testing.T) {
+		source := `[
+			{"term": "base:appName", "definition": "Bank", "term_plural": ""},
+			{"term": "brand:appName", "definition": "Brand Bank", "term_plural": ""},
+			{"term": "season:appName", "definition": "Winter Bank", "term_plural": ""},
+			{"term": "base:email", "definition": "help@bank.com", "term_plural": ""},
+			{"term": "brand:email", "definition": "help@brand.com", "term_plural": ""},
+			{"term": "season:email", "definition": "", "term_plural": ""},
+			{"term": "base:title", "definition": "Welcome", "term_plural": ""},
+			{"term": "other:title", "definition": "Other", "term_plural": ""}
+		]`
+
+		arb, err := convertWith(source, true, "brand", "season")
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+    "@@locale": "en",
+    "appName": "Winter Bank",
+    "email": "help@brand.com",
+    "title": "Welcome"
+}
+`, arb)
+
+		arb, err = convertWith(source, true)
+		assert.NoError(t, err)
+		assert.Contains(t, arb, `"appName": "Bank"`)
+	})
+
+	t.Run("override without base term", func(t *testing.T) {
+		source := `[
+			{"term": "brand:promo", "definition": "Promo", "term_plural": ""}
+		]`
+
+		_, err := convertWith(source, true, "brand")
+		assert.EqualError(t, err, `term "promo" is overridden with brand prefix, but there is no base term`)
+	})
+
+	t.Run("template placeholders differ", func(t *testing.T) {
+		source := `[
+			{"term": "base:greeting", "definition": "Hello, {name,String}!", "term_plural": ""},
+			{"term": "brand:greeting", "definition": "Hi, {user,String}!", "term_plural": ""},
+			{"term": "base:balance", "definition": "{amount,double} EUR", "term_plural": ""},
+			{"term": "brand:balance", "definition": "{amount,int} EUR", "term_plural": ""}
+		]`
+
+		_, err := convertWith(source, true, "brand")
+		assert.EqualError(t, err, "balance: placeholders of the brand override differ from the base term\n"+
+			"greeting: placeholders of the brand override differ from the base term")
+
+		_, err = convertWith(source, false, "brand")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,19 @@
+package poe2arb
+
+// Coverage describes how many of the converted messages are translated.
+type Coverage struct {
+	// Total is the number of messages, translated or not.
+	Total int
+	// Missing are names of the messages with no translation.
+	Missing []string
+}
+
+// Percentage returns the percentage of translated messages.
+// A language with no messages is fully translated.
+func (c *Coverage) Percentage() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+
+	return 100 * float64(c.Total-len(c.Missing)) / float64(c.Total)
+}
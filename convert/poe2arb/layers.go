@@ -0,0 +1,124 @@
+package poe2arb
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// LayerPlaceholderMismatch is a template term overridden by a term
+// whose placeholder definitions differ.
+type LayerPlaceholderMismatch struct {
+	Term   string
+	Prefix string
+}
+
+func (m *LayerPlaceholderMismatch) Error() string {
+	return fmt.Sprintf("%s: placeholders of the %s override differ from the base term", m.Term, m.Prefix)
+}
+
+// layeredTerm is a term along with the index of the prefix layer it comes from.
+// Layer 0 is the term prefix, the following ones are the override prefixes.
+type layeredTerm struct {
+	term  *convert.POETerm
+	layer int
+}
+
+// applyOverrides filters the terms by the term prefix and override prefixes, stripping the prefixes.
+// For each term name, the term of the last override prefix having a translation is used,
+// falling back to the base one. Overridden terms must have a base term and, in the template,
+// the same placeholder definitions as it.
+func (c *Converter) applyOverrides(terms []*convert.POETerm) ([]*convert.POETerm, []error) {
+	prefixes := append([]string{c.termPrefix}, c.overridePrefixes...)
+
+	layers := orderedmap.New[string, []*layeredTerm]()
+	for _, term := range terms {
+		matches := prefixedTermRegexp.FindStringSubmatch(term.Term)
+		layer := slices.Index(prefixes, matches[1])
+		if layer == -1 {
+			continue
+		}
+
+		// The terms may be shared with other conversions, so the prefix is stripped from a copy.
+		stripped := *term
+		stripped.Term = matches[2]
+		layered, _ := layers.Get(stripped.Term)
+		layers.Set(stripped.Term, append(layered, &layeredTerm{term: &stripped, layer: layer}))
+	}
+
+	var result []*convert.POETerm
+	var errs []error
+	for pair := layers.Oldest(); pair != nil; pair = pair.Next() {
+		var base, override *layeredTerm
+		for _, layered := range pair.Value {
+			if layered.layer == 0 {
+				base = layered
+			} else if !isEmptyDefinition(layered.term.Definition) && (override == nil || layered.layer > override.layer) {
+				override = layered
+			}
+		}
+
+		if base == nil {
+			var overridePrefixes []string
+			for _, layered := range pair.Value {
+				overridePrefixes = append(overridePrefixes, prefixes[layered.layer])
+			}
+			errs = append(errs, fmt.Errorf(`term "%s" is overridden with %s prefix, but there is no base term`,
+				pair.Key, strings.Join(overridePrefixes, ", ")))
+			continue
+		}
+
+		if override == nil {
+			result = append(result, base.term)
+			continue
+		}
+
+		if c.template && !c.samePlaceholders(base.term, override.term) {
+			errs = append(errs, &LayerPlaceholderMismatch{Term: pair.Key, Prefix: prefixes[override.layer]})
+			continue
+		}
+
+		result = append(result, override.term)
+	}
+
+	return result, errs
+}
+
+// samePlaceholders reports whether both terms define the same placeholders.
+// Terms failing to parse are reported by Convert, so they are considered the same here.
+func (c *Converter) samePlaceholders(a, b *convert.POETerm) bool {
+	aMessage, _, err := c.parseTerm(a)
+	if err != nil || aMessage == nil {
+		return true
+	}
+	bMessage, _, err := c.parseTerm(b)
+	if err != nil || bMessage == nil {
+		return true
+	}
+
+	aPlaceholders, bPlaceholders := aMessage.Attributes.Placeholders, bMessage.Attributes.Placeholders
+	if aPlaceholders.Len() != bPlaceholders.Len() {
+		return false
+	}
+
+	for pair := aPlaceholders.Oldest(); pair != nil; pair = pair.Next() {
+		bPlaceholder, ok := bPlaceholders.Get(pair.Key)
+		if !ok || !reflect.DeepEqual(pair.Value, bPlaceholder) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEmptyDefinition(definition convert.POETermDefinition) bool {
+	if definition.IsPlural {
+		return definition.Plural == nil || definition.Plural.Other == ""
+	}
+
+	return definition.Value == nil || *definition.Value == ""
+}
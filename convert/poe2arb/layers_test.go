@@ -0,0 +1,26 @@
+package poe2arb
+
+import (
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyOverridesKeepsTerms(t *testing.T) {
+	bank, brandBank := "Bank", "Brand Bank"
+	terms := []*convert.POETerm{
+		{Term: "base:appName", Definition: convert.POETermDefinition{Value: &bank}},
+		{Term: "brand:appName", Definition: convert.POETermDefinition{Value: &brandBank}},
+	}
+
+	conv := NewConverter(nil, &ConverterOptions{TermPrefix: "base", OverridePrefixes: []string{"brand"}})
+	result, errs := conv.applyOverrides(terms)
+	assert.Empty(t, errs)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "appName", result[0].Term)
+	assert.Equal(t, "Brand Bank", *result[0].Definition.Value)
+
+	assert.Equal(t, "base:appName", terms[0].Term)
+	assert.Equal(t, "brand:appName", terms[1].Term)
+}
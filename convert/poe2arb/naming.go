@@ -0,0 +1,101 @@
+package poe2arb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/flutter"
+)
+
+// NamingStrategy decides how POEditor term names are turned into ARB message names.
+type NamingStrategy string
+
+const (
+	// NamingKeep keeps the term name, only lower-casing the first letter
+	// and replacing dots with underscores, e.g. Login.title becomes login_title.
+	NamingKeep NamingStrategy = "keep"
+	// NamingDotToCamel joins dot-separated parts in camelCase, e.g. login.page_title becomes loginPage_title.
+	NamingDotToCamel NamingStrategy = "dot-to-camel"
+	// NamingSnakeToCamel joins dot- and underscore-separated parts in camelCase,
+	// e.g. login.page_title becomes loginPageTitle.
+	NamingSnakeToCamel NamingStrategy = "snake-to-camel"
+)
+
+var NamingStrategies = []NamingStrategy{
+	NamingKeep,
+	NamingDotToCamel,
+	NamingSnakeToCamel,
+}
+
+var messageNameRegexp = regexp.MustCompile(`^[a-z][a-zA-Z_\d]*$`)
+
+// dartReservedWords can't be used as getter names in the generated localizations class.
+// https://dart.dev/language/keywords
+var dartReservedWords = []string{
+	"assert", "break", "case", "catch", "class", "const", "continue", "default",
+	"do", "else", "enum", "extends", "false", "final", "finally", "for", "if",
+	"in", "is", "new", "null", "rethrow", "return", "super", "switch", "this",
+	"throw", "true", "try", "var", "void", "while", "with",
+}
+
+// MessageName returns the ARB message name of a POEditor term name without its term prefix,
+// normalized with the strategy.
+// The name must be a valid Dart getter name, see https://github.com/flutter/flutter/blob/fae84f67140cbaa7a07ed5c82ee99f31c7bb1f0e/packages/flutter_tools/lib/src/localizations/gen_l10n.dart#L1056
+func MessageName(name string, strategy NamingStrategy) (string, error) {
+	switch strategy {
+	case NamingDotToCamel:
+		name = joinCamelCase(strings.Split(name, "."))
+	case NamingSnakeToCamel:
+		name = joinCamelCase(strings.FieldsFunc(name, func(r rune) bool {
+			return r == '.' || r == '_'
+		}))
+	default:
+		name = strings.ReplaceAll(name, ".", "_")
+	}
+
+	if name == "" {
+		return "", errors.New("term name is empty")
+	}
+
+	// lowercase first letter, Flutter gen-l10n doesn't allow first letter uppercase
+	name = strings.ToLower(name[:1]) + name[1:]
+
+	if !messageNameRegexp.MatchString(name) {
+		return "", errors.New("term name must start with lowercase letter followed by any number of anycase letter, digit or underscore")
+	}
+
+	if slices.Contains(dartReservedWords, name) {
+		return "", fmt.Errorf("message name %s is a reserved word in Dart", name)
+	}
+
+	if slices.Contains(flutter.LocalizationsMembers, name) {
+		return "", fmt.Errorf("message name %s clashes with a member of the generated localizations class", name)
+	}
+
+	return name, nil
+}
+
+// joinCamelCase joins the parts, upper-casing first letters of all but the first one.
+func joinCamelCase(parts []string) string {
+	var sb strings.Builder
+	for i, part := range parts {
+		if i > 0 && part != "" {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// NameCollision describes POEditor terms which got the same message name.
+type NameCollision struct {
+	Message string
+	Terms   []string
+}
+
+func (c *NameCollision) Error() string {
+	return fmt.Sprintf("%s: terms %s have the same message name", c.Message, strings.Join(c.Terms, ", "))
+}
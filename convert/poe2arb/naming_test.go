@@ -0,0 +1,57 @@
+package poe2arb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseName(t *testing.T) {
+	type testCase struct {
+		Input        string
+		Strategy     NamingStrategy
+		ExpectedName string
+		ExpectedErr  string
+	}
+
+	cases := []testCase{
+		{"someName", NamingKeep, "someName", ""},
+		{"some_name", NamingKeep, "some_name", ""},
+		{"some.name", NamingKeep, "some_name", ""},
+		{"some.....name", NamingKeep, "some_____name", ""},
+		{"someName1", NamingKeep, "someName1", ""},
+		{"SomeName", NamingKeep, "someName", ""},
+		{"some/name", NamingKeep, "", "term name must start with lowercase letter followed by any number of anycase letter, digit or underscore"},
+		{"some-name", NamingKeep, "", "term name must start with lowercase letter followed by any number of anycase letter, digit or underscore"},
+		{"_someName", NamingKeep, "", "term name must start with lowercase letter followed by any number of anycase letter, digit or underscore"},
+		{"1someName", NamingKeep, "", "term name must start with lowercase letter followed by any number of anycase letter, digit or underscore"},
+		{"", NamingKeep, "", "term name is empty"},
+
+		{"login.page_title", NamingDotToCamel, "loginPage_title", ""},
+		{"Login.title", NamingDotToCamel, "loginTitle", ""},
+		{"login..title", NamingDotToCamel, "loginTitle", ""},
+
+		{"login.page_title", NamingSnakeToCamel, "loginPageTitle", ""},
+		{"login_title", NamingSnakeToCamel, "loginTitle", ""},
+		{"login__title_2", NamingSnakeToCamel, "loginTitle2", ""},
+
+		{"class", NamingKeep, "", "message name class is a reserved word in Dart"},
+		{"New", NamingKeep, "", "message name new is a reserved word in Dart"},
+		{"localeName", NamingKeep, "", "message name localeName clashes with a member of the generated localizations class"},
+		{"locale_name", NamingSnakeToCamel, "", "message name localeName clashes with a member of the generated localizations class"},
+		{"toString", NamingKeep, "", "message name toString clashes with a member of the generated localizations class"},
+		{"className", NamingKeep, "className", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.Strategy)+" "+c.Input, func(t *testing.T) {
+			name, err := MessageName(c.Input, c.Strategy)
+			assert.Equal(t, c.ExpectedName, name)
+			if c.ExpectedErr != "" {
+				assert.EqualError(t, err, c.ExpectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -4,41 +4,35 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 )
 
-const (
-	messageParameterPattern = `[a-zA-Z][a-zA-Z_\d]*`
-	countPlaceholderName    = "count"
-)
-
-var (
-	messageNameRegexp = regexp.MustCompile(`^[a-z][a-zA-Z_\d]*$`)
-	placeholderRegexp = regexp.MustCompile(`{(` + messageParameterPattern + `)(?:,([a-zA-Z]+)(?:,([a-zA-Z+]+))?)?}`)
-)
+const selectPlaceholderType = "String"
 
-func parseName(name string) (string, error) {
-	// lowercase first letter, Flutter gen-l10n doesn't allow first letter uppercase
-	// https://github.com/flutter/flutter/blob/fae84f67140cbaa7a07ed5c82ee99f31c7bb1f0e/packages/flutter_tools/lib/src/localizations/gen_l10n.dart#L1056
-	name = strings.ToLower(name[:1]) + name[1:]
-
-	// replace dots with an underscore
-	name = strings.ReplaceAll(name, ".", "_")
-
-	if !messageNameRegexp.MatchString(name) {
-		return "", errors.New("term name must start with lowercase letter followed by any number of anycase letter, digit or underscore")
-	}
-
-	return name, nil
-}
+var pluralCaseRegexp = regexp.MustCompile(`^(=\d+|zero|one|two|few|many|other)$`)
 
 type translationParser struct {
-	plural bool
-
-	namedParams *orderedmap.OrderedMap[string, *placeholder]
+	// countName is the placeholder driving a plural term, empty for non-plural terms.
+	countName string
+	// escaping enables ICU quoting with apostrophes.
+	escaping bool
+	// strict reports brackets which don't start an argument as errors, see icu.Options.Relaxed,
+	// and syntax errors of non-template translations.
+	strict bool
+
+	namedParams  *orderedmap.OrderedMap[string, *placeholder]
+	namedOptions map[string]*placeholderOptions
+	selectParams map[string]bool
+	pluralParams map[string]bool
+
+	// usedNames are names of all placeholders used in the parsed translations,
+	// collected also by ParseDummy.
+	usedNames []string
 }
 
 type placeholder struct {
@@ -46,42 +40,201 @@ type placeholder struct {
 	Format string
 }
 
-func newTranslationParser(plural bool) *translationParser {
-	return &translationParser{
-		plural:      plural,
-		namedParams: orderedmap.New[string, *placeholder](),
+// newPluralTranslationParser creates a parser for a plural term driven by countName placeholder.
+// Empty countName creates a parser for non-plural terms.
+func newPluralTranslationParser(countName string) *translationParser {
+	tp := &translationParser{
+		countName:    countName,
+		namedParams:  orderedmap.New[string, *placeholder](),
+		namedOptions: map[string]*placeholderOptions{},
+		selectParams: map[string]bool{},
+		pluralParams: map[string]bool{},
 	}
+
+	if countName != "" {
+		tp.pluralParams[countName] = true
+		tp.use(countName)
+	}
+
+	return tp
 }
 
 // ParseDummy is used to parse a translation string without actually adding the placeholders to the parser
-// and checking for errors. Used for non-template terms.
-func (tp *translationParser) ParseDummy(translation string) string {
-	return placeholderRegexp.ReplaceAllString(translation, "{$1}")
+// and checking for placeholder errors. Syntax errors are returned only in strict mode, otherwise
+// the translation is copied verbatim. Used for non-template terms.
+func (tp *translationParser) ParseDummy(translation string) (string, error) {
+	options := icu.Options{Escaping: tp.escaping, Relaxed: !tp.strict}
+
+	m, err := icu.Parse(translation, options)
+	if err != nil {
+		if !tp.strict {
+			return translation, nil
+		}
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+	tp.useNames(m)
+
+	return icu.Print(withoutArgumentTypes(m), options), nil
 }
 
 func (tp *translationParser) Parse(translation string) (string, error) {
-	var errors translationParserErrors
+	options := icu.Options{Escaping: tp.escaping, Relaxed: !tp.strict}
 
-	replaced := placeholderRegexp.ReplaceAllStringFunc(translation, func(match string) string {
-		matchGroup := placeholderRegexp.FindStringSubmatch(match)
-		name, placeholderType, format := matchGroup[1], matchGroup[2], matchGroup[3]
+	m, err := icu.Parse(translation, options)
+	if err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+	tp.useNames(m)
+
+	var errs translationParserErrors
+	icu.Walk(m, func(node icu.Node) {
+		switch n := node.(type) {
+		case *icu.Argument:
+			if err := tp.addPlaceholderDefinition(n.Name, n.Type, n.Style); err != nil {
+				errs.AddError(n.Name, err)
+			}
+
+		case *icu.Select:
+			if err := tp.addSelectPlaceholder(n.Name); err != nil {
+				errs.AddError(n.Name, err)
+			}
+			if !hasOtherCase(n.Cases) {
+				errs.AddError(n.Name, errors.New(`missing "other" select case`))
+			}
+
+		case *icu.Plural:
+			if err := tp.addPluralPlaceholder(n.Name); err != nil {
+				errs.AddError(n.Name, err)
+			}
+			for _, c := range n.Cases {
+				if !pluralCaseRegexp.MatchString(c.Key) {
+					errs.AddError(n.Name, fmt.Errorf("invalid plural category %s", c.Key))
+				}
+			}
+			if !hasOtherCase(n.Cases) {
+				errs.AddError(n.Name, errors.New(`missing "other" plural category`))
+			}
+		}
+	})
 
-		err := tp.addPlaceholder(name, placeholderType, format)
-		if err != nil {
-			errors.AddError(name, err)
+	if errs.HasErrors() {
+		return "", errs
+	}
+
+	return icu.Print(withoutArgumentTypes(m), options), nil
+}
+
+func hasOtherCase(cases []*icu.Case) bool {
+	return slices.ContainsFunc(cases, func(c *icu.Case) bool { return c.Key == "other" })
+}
+
+// withoutArgumentTypes returns a copy of the message with placeholder definitions
+// reduced to placeholder names, as in ARB files.
+func withoutArgumentTypes(m icu.Message) icu.Message {
+	result := make(icu.Message, 0, len(m))
+	for _, node := range m {
+		switch n := node.(type) {
+		case *icu.Argument:
+			result = append(result, &icu.Argument{Name: n.Name})
+		case *icu.Plural:
+			result = append(result, &icu.Plural{Name: n.Name, Cases: casesWithoutArgumentTypes(n.Cases)})
+		case *icu.Select:
+			result = append(result, &icu.Select{Name: n.Name, Cases: casesWithoutArgumentTypes(n.Cases)})
+		default:
+			result = append(result, node)
 		}
+	}
+	return result
+}
 
-		return "{" + name + "}"
+func casesWithoutArgumentTypes(cases []*icu.Case) []*icu.Case {
+	result := make([]*icu.Case, 0, len(cases))
+	for _, c := range cases {
+		result = append(result, &icu.Case{Key: c.Key, Message: withoutArgumentTypes(c.Message)})
+	}
+	return result
+}
+
+// useNames marks all placeholders of the message as used, including select and plural variables.
+func (tp *translationParser) useNames(m icu.Message) {
+	icu.Walk(m, func(node icu.Node) {
+		switch n := node.(type) {
+		case *icu.Argument:
+			tp.use(n.Name)
+		case *icu.Plural:
+			tp.use(n.Name)
+		case *icu.Select:
+			tp.use(n.Name)
+		}
 	})
+}
 
-	if errors.HasErrors() {
-		return "", errors
+func (tp *translationParser) use(name string) {
+	if !slices.Contains(tp.usedNames, name) {
+		tp.usedNames = append(tp.usedNames, name)
 	}
+}
 
-	return replaced, nil
+// UsedPlaceholders returns names of the placeholders used in the parsed translations,
+// including select and plural variables.
+func (tp *translationParser) UsedPlaceholders() []string {
+	return tp.usedNames
 }
 
-func (tp *translationParser) addPlaceholder(name, placeholderType, format string) error {
+func (tp *translationParser) addSelectPlaceholder(name string) error {
+	if placeholder, _ := tp.namedParams.Get(name); placeholder != nil && placeholder.Type != selectPlaceholderType {
+		return fmt.Errorf("select placeholder must be of %s type", selectPlaceholderType)
+	}
+
+	tp.selectParams[name] = true
+	tp.namedParams.Set(name, &placeholder{selectPlaceholderType, ""})
+
+	return nil
+}
+
+func (tp *translationParser) addPluralPlaceholder(name string) error {
+	placeholder, present := tp.namedParams.Get(name)
+	if placeholder != nil && !tp.pluralParams[name] && !isCountPlaceholderType(placeholder.Type) {
+		return errors.New("invalid count placeholder type. Supported types: num, int")
+	}
+
+	tp.pluralParams[name] = true
+	if !present {
+		// filled in by fallbackPlaceholderTypes
+		tp.namedParams.Set(name, nil)
+	}
+
+	return nil
+}
+
+func isCountPlaceholderType(placeholderType string) bool {
+	return placeholderType == "num" || placeholderType == "int"
+}
+
+// addPlaceholderDefinition adds a placeholder with options not yet parsed.
+func (tp *translationParser) addPlaceholderDefinition(name, placeholderType, rawOptions string) error {
+	format, options, err := parsePlaceholderOptions(rawOptions)
+	if err != nil {
+		return err
+	}
+
+	if placeholderType == "" && rawOptions != "" {
+		return errors.New("placeholder options require a type")
+	}
+
+	return tp.addPlaceholderWithOptions(name, placeholderType, format, options)
+}
+
+func (tp *translationParser) addPlaceholderWithOptions(name, placeholderType, format string, options *placeholderOptions) error {
+	if tp.selectParams[name] {
+		// already defined by the select expression
+		if placeholderType == "" || placeholderType == selectPlaceholderType && format == "" {
+			return nil
+		}
+
+		return fmt.Errorf("select placeholder must be of %s type", selectPlaceholderType)
+	}
+
 	if placeholder, present := tp.namedParams.Get(name); placeholder != nil {
 		_ = present
 		// present == false - placeholder was never seen
@@ -94,7 +247,7 @@ func (tp *translationParser) addPlaceholder(name, placeholderType, format string
 		}
 	}
 
-	if tp.plural && name == countPlaceholderName {
+	if tp.pluralParams[name] {
 		switch placeholderType {
 		case "":
 			// filled in by fallbackPlaceholderTypes
@@ -102,7 +255,11 @@ func (tp *translationParser) addPlaceholder(name, placeholderType, format string
 			return nil
 
 		case "num", "int":
-			tp.namedParams.Set(name, &placeholder{placeholderType, format})
+			if err := validateNumberFormat(format, options); err != nil {
+				return err
+			}
+
+			tp.setPlaceholder(name, &placeholder{placeholderType, format}, options)
 			return nil
 
 		default:
@@ -118,15 +275,19 @@ func (tp *translationParser) addPlaceholder(name, placeholderType, format string
 
 	switch placeholderType {
 	case "num", "int", "double":
-		tp.namedParams.Set(name, &placeholder{placeholderType, format})
+		if err := validateNumberFormat(format, options); err != nil {
+			return err
+		}
+
+		tp.setPlaceholder(name, &placeholder{placeholderType, format}, options)
 		return nil
 
 	case "String", "Object":
-		if format != "" {
+		if format != "" || options.OptionalParameters != nil {
 			return fmt.Errorf("format is not supported for %s placeholders", placeholderType)
 		}
 
-		tp.namedParams.Set(name, &placeholder{placeholderType, ""})
+		tp.setPlaceholder(name, &placeholder{placeholderType, ""}, options)
 		return nil
 
 	case "DateTime":
@@ -134,7 +295,11 @@ func (tp *translationParser) addPlaceholder(name, placeholderType, format string
 			return errors.New("format is required for DateTime placeholders")
 		}
 
-		tp.namedParams.Set(name, &placeholder{"DateTime", format})
+		if err := validateDateFormat(format, options); err != nil {
+			return err
+		}
+
+		tp.setPlaceholder(name, &placeholder{"DateTime", format}, options)
 		return nil
 
 	default:
@@ -142,6 +307,11 @@ func (tp *translationParser) addPlaceholder(name, placeholderType, format string
 	}
 }
 
+func (tp *translationParser) setPlaceholder(name string, p *placeholder, options *placeholderOptions) {
+	tp.namedParams.Set(name, p)
+	tp.namedOptions[name] = options
+}
+
 func (tp *translationParser) BuildMessageAttributes() *convert.ARBMessageAttributes {
 	tp.fallbackPlaceholderTypes()
 
@@ -156,6 +326,14 @@ func (tp *translationParser) BuildMessageAttributes() *convert.ARBMessageAttribu
 			Format: placeholder.Format,
 		}
 
+		if options := tp.namedOptions[name]; options != nil {
+			arbPlaceholder.OptionalParameters = options.OptionalParameters
+			arbPlaceholder.Example = options.Example
+			if options.IsCustomDateFormat {
+				arbPlaceholder.IsCustomDateFormat = "true"
+			}
+		}
+
 		placeholders = append(placeholders, arbPlaceholder)
 	}
 
@@ -172,9 +350,9 @@ func (tp *translationParser) BuildMessageAttributes() *convert.ARBMessageAttribu
 }
 
 func (tp *translationParser) fallbackPlaceholderTypes() {
-	_, hasCountPlaceholder := tp.namedParams.Get(countPlaceholderName)
-	if tp.plural && !hasCountPlaceholder {
-		tp.namedParams.Set(countPlaceholderName, &placeholder{"", ""})
+	_, hasCountPlaceholder := tp.namedParams.Get(tp.countName)
+	if tp.countName != "" && !hasCountPlaceholder {
+		tp.namedParams.Set(tp.countName, &placeholder{"", ""})
 	}
 
 	for pair := tp.namedParams.Oldest(); pair != nil; pair = pair.Next() {
@@ -184,7 +362,7 @@ func (tp *translationParser) fallbackPlaceholderTypes() {
 			continue
 		}
 
-		if tp.plural && name == countPlaceholderName {
+		if tp.pluralParams[name] {
 			tp.namedParams.Set(name, &placeholder{"", ""})
 		} else {
 			// Flutter uses Object as the default type, but we want to use String
@@ -195,6 +373,8 @@ func (tp *translationParser) fallbackPlaceholderTypes() {
 }
 
 type translationParserErrors struct {
+	// names keep the order placeholders were reported in
+	names  []string
 	errors map[string][]error
 }
 
@@ -203,6 +383,9 @@ func (e *translationParserErrors) AddError(placeholderName string, err error) {
 		e.errors = map[string][]error{}
 	}
 
+	if _, ok := e.errors[placeholderName]; !ok {
+		e.names = append(e.names, placeholderName)
+	}
 	e.errors[placeholderName] = append(e.errors[placeholderName], err)
 }
 
@@ -215,8 +398,8 @@ func (e translationParserErrors) Error() string {
 
 	sb.WriteString("some errors occurred while parsing translation:")
 
-	for placeholderName, errs := range e.errors {
-		for _, err := range errs {
+	for _, placeholderName := range e.names {
+		for _, err := range e.errors[placeholderName] {
 			sb.WriteString(fmt.Sprintf("\n  - %s: %s", placeholderName, err))
 		}
 	}
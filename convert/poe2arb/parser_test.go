@@ -4,41 +4,10 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/leancodepl/poe2arb/convert"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestParseName(t *testing.T) {
-	type testCase struct {
-		Input        string
-		ExpectedName string
-		ExpectError  bool
-	}
-
-	cases := []testCase{
-		{"someName", "someName", false},
-		{"some_name", "some_name", false},
-		{"some.name", "some_name", false},
-		{"some.....name", "some_____name", false},
-		{"someName1", "someName1", false},
-		{"some/name", "", true},
-		{"some-name", "", true},
-		{"_someName", "", true},
-		{"1someName", "", true},
-	}
-
-	for _, c := range cases {
-		t.Run(c.Input, func(t *testing.T) {
-			name, err := parseName(c.Input)
-			assert.Equal(t, c.ExpectedName, name)
-			if c.ExpectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-		})
-	}
-}
-
 func TestTranslationParseDummy(t *testing.T) {
 	type testCase struct {
 		Input          string
@@ -49,23 +18,51 @@ func TestTranslationParseDummy(t *testing.T) {
 		{"some text", "some text"},
 		{"some {placeholder} text", "some {placeholder} text"},
 		{"a {placeholder} with {placeholder,DateTime,yMd} {default} and {default}", "a {placeholder} with {placeholder} {default} and {default}"},
+		{"{gender,select,male{He is {age,int}} other{They are {age}}}", "{gender, select, male {He is {age}} other {They are {age}}}"},
+		{`{price,double,currency(name: "EUR"),example: "{€1}"} on {date,DateTime,"y-MM-dd"}`, "{price} on {date}"},
 	}
 
 	for _, testCase := range cases {
 		t.Run(testCase.Input, func(t *testing.T) {
-			parser := newTranslationParser(false)
+			parser := newPluralTranslationParser("")
 
-			output := parser.ParseDummy(testCase.Input)
+			output, err := parser.ParseDummy(testCase.Input)
 
+			assert.NoError(t, err)
 			assert.Equal(t, testCase.ExpectedOutput, output)
 		})
 	}
 }
 
+func TestTranslationParseDummyErrors(t *testing.T) {
+	for _, input := range []string{"{count,int", "{gender, select, male {He}"} {
+		t.Run(input, func(t *testing.T) {
+			parser := newPluralTranslationParser("")
+
+			output, err := parser.ParseDummy(input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, input, output)
+			assert.Empty(t, parser.UsedPlaceholders())
+		})
+
+		t.Run(input+" strict", func(t *testing.T) {
+			parser := newPluralTranslationParser("")
+			parser.strict = true
+
+			output, err := parser.ParseDummy(input)
+
+			assert.ErrorContains(t, err, "invalid message")
+			assert.Empty(t, output)
+			assert.Empty(t, parser.UsedPlaceholders())
+		})
+	}
+}
+
 func TestTranslationParserParseErrors(t *testing.T) {
 	type testCase struct {
 		TestName             string
-		Plural               bool
+		CountName            string
 		Input                string
 		ExpectedOutput       string
 		ExpectedPlaceholders map[string]*placeholder
@@ -95,16 +92,87 @@ func TestTranslationParserParseErrors(t *testing.T) {
 				"default":     nil,
 			},
 		},
+		{
+			TestName:       "placeholder with options",
+			Input:          `{price,double,currency(name: "EUR", decimalDigits: 2),example: "€1.00"} on {date,DateTime,"EEE, M/d/y"}`,
+			ExpectedOutput: "{price} on {date}",
+			ExpectedPlaceholders: map[string]*placeholder{
+				"price": {"double", "currency"},
+				"date":  {"DateTime", "EEE, M/d/y"},
+			},
+		},
+		{
+			TestName:      "placeholder with invalid options",
+			Input:         `{price,double,currency(color: "red")} and {date,String,"y"}`,
+			ExpectedError: "some errors occurred while parsing translation:\n  - price: unknown parameter color of number format currency\n  - date: format is not supported for String placeholders",
+		},
 		{
 			TestName:      "placeholder double definitions",
 			Input:         "{placeholder,String} with {placeholder,DateTime,yMd}",
 			ExpectedError: "some errors occurred while parsing translation:\n  - placeholder: placeholder type can only be defined once",
 		},
+		{
+			TestName:       "select",
+			Input:          "{gender,select,male{He} female {She}  other {They}}",
+			ExpectedOutput: "{gender, select, male {He} female {She} other {They}}",
+			ExpectedPlaceholders: map[string]*placeholder{
+				"gender": {"String", ""},
+			},
+		},
+		{
+			TestName:       "select with placeholders in text",
+			Input:          "{name} said {gender, select, male {he is {age,int}} other {they are {age}}} with {gender,String} pronouns",
+			ExpectedOutput: "{name} said {gender, select, male {he is {age}} other {they are {age}}} with {gender} pronouns",
+			ExpectedPlaceholders: map[string]*placeholder{
+				"name":   nil,
+				"gender": {"String", ""},
+				"age":    {"int", ""},
+			},
+		},
+		{
+			TestName:      "select without other case",
+			Input:         "{gender, select, male {He} female {She}}",
+			ExpectedError: "some errors occurred while parsing translation:\n  - gender: missing \"other\" select case",
+		},
+		{
+			TestName:      "select with a non-String placeholder",
+			Input:         "{gender,int} {gender, select, male {He} other {They}}",
+			ExpectedError: "some errors occurred while parsing translation:\n  - gender: select placeholder must be of String type",
+		},
+		{
+			TestName:       "plural nested in text",
+			Input:          "You have {itemCount,plural, =1{1 item} other{{itemCount,int} items}} in {cartName}",
+			ExpectedOutput: "You have {itemCount, plural, =1 {1 item} other {{itemCount} items}} in {cartName}",
+			ExpectedPlaceholders: map[string]*placeholder{
+				"itemCount": {"int", ""},
+				"cartName":  nil,
+			},
+		},
+		{
+			TestName:      "nested plural with a String count",
+			Input:         "{itemCount, plural, other {{itemCount,String} items}}",
+			ExpectedError: "some errors occurred while parsing translation:\n  - itemCount: invalid count placeholder type. Supported types: num, int",
+		},
+		{
+			TestName:      "nested plural with invalid category",
+			Input:         "{itemCount, plural, several {some} other {many}}",
+			ExpectedError: "some errors occurred while parsing translation:\n  - itemCount: invalid plural category several",
+		},
+		{
+			TestName:      "nested plural without other category",
+			Input:         "{itemCount, plural, one {one}}",
+			ExpectedError: "some errors occurred while parsing translation:\n  - itemCount: missing \"other\" plural category",
+		},
+		{
+			TestName:      "unclosed select",
+			Input:         "{gender, select, male {He} other {They}",
+			ExpectedError: "invalid message: unterminated argument at offset 0",
+		},
 	}
 
 	for _, testCase := range cases {
 		t.Run(testCase.TestName, func(t *testing.T) {
-			parser := newTranslationParser(testCase.Plural)
+			parser := newPluralTranslationParser(testCase.CountName)
 
 			output, err := parser.Parse(testCase.Input)
 
@@ -130,7 +198,7 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 	type testCase struct {
 		TestName string
 
-		Plural              bool
+		CountName           string
 		InitialPlaceholders map[string]*placeholder
 
 		Name   string
@@ -175,20 +243,27 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 			TestName: "name, type DateTime and format",
 			Name:     "param",
 			Type:     "DateTime",
-			Format:   "format",
+			Format:   "yMd",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"param": {"DateTime", "format"},
+				"param": {"DateTime", "yMd"},
 			},
 		},
 		{
 			TestName: "name, type DateTime and full date-time format",
 			Name:     "param",
 			Type:     "DateTime",
-			Format:   "date-format+time-format",
+			Format:   "yMd+jms",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"param": {"DateTime", "date-format+time-format"},
+				"param": {"DateTime", "yMd+jms"},
 			},
 		},
+		{
+			TestName:      "name, type DateTime and unknown format",
+			Name:          "param",
+			Type:          "DateTime",
+			Format:        "format",
+			ExpectedError: `unknown date format format, use a quoted pattern for custom date formats, e.g. "yyyy-MM-dd"`,
+		},
 		{
 			TestName:      "name, type DateTime and no format",
 			Name:          "param",
@@ -199,29 +274,36 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 			TestName: "name, type num and format",
 			Name:     "param",
 			Type:     "num",
-			Format:   "format",
+			Format:   "compact",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"param": {"num", "format"},
+				"param": {"num", "compact"},
 			},
 		},
 		{
 			TestName: "name, type int and format",
 			Name:     "param",
 			Type:     "int",
-			Format:   "format",
+			Format:   "compact",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"param": {"int", "format"},
+				"param": {"int", "compact"},
 			},
 		},
 		{
 			TestName: "name, type double and format",
 			Name:     "param",
 			Type:     "double",
-			Format:   "format",
+			Format:   "compact",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"param": {"double", "format"},
+				"param": {"double", "compact"},
 			},
 		},
+		{
+			TestName:      "name, type double and unknown format",
+			Name:          "param",
+			Type:          "double",
+			Format:        "format",
+			ExpectedError: "unknown number format format",
+		},
 		{
 			TestName: "name, type double and no format",
 			Name:     "param",
@@ -265,55 +347,55 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 		//
 		{
 			TestName:      "name count, type String in plural",
-			Plural:        true,
+			CountName:     convert.DefaultCountPlaceholderName,
 			Name:          "count",
 			Type:          "String",
 			ExpectedError: "invalid count placeholder type. Supported types: num, int",
 		},
 		{
-			TestName: "just name count in plural",
-			Plural:   true,
-			Name:     "count",
+			TestName:  "just name count in plural",
+			CountName: convert.DefaultCountPlaceholderName,
+			Name:      "count",
 			ExpectedPlaceholders: map[string]*placeholder{
 				"count": nil,
 			},
 		},
 		{
-			TestName: "name count, type num, without format in plural",
-			Plural:   true,
-			Name:     "count",
-			Type:     "num",
+			TestName:  "name count, type num, without format in plural",
+			CountName: convert.DefaultCountPlaceholderName,
+			Name:      "count",
+			Type:      "num",
 			ExpectedPlaceholders: map[string]*placeholder{
 				"count": {"num", ""},
 			},
 		},
 		{
-			TestName: "name count, type num, with format in plural",
-			Plural:   true,
-			Name:     "count",
-			Type:     "num",
-			Format:   "format",
+			TestName:  "name count, type num, with format in plural",
+			CountName: convert.DefaultCountPlaceholderName,
+			Name:      "count",
+			Type:      "num",
+			Format:    "decimalPattern",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"count": {"num", "format"},
+				"count": {"num", "decimalPattern"},
 			},
 		},
 		{
-			TestName: "name count, type int, without format in plural",
-			Plural:   true,
-			Name:     "count",
-			Type:     "int",
+			TestName:  "name count, type int, without format in plural",
+			CountName: convert.DefaultCountPlaceholderName,
+			Name:      "count",
+			Type:      "int",
 			ExpectedPlaceholders: map[string]*placeholder{
 				"count": {"int", ""},
 			},
 		},
 		{
-			TestName: "name count, type int, with format in plural",
-			Plural:   true,
-			Name:     "count",
-			Type:     "int",
-			Format:   "format",
+			TestName:  "name count, type int, with format in plural",
+			CountName: convert.DefaultCountPlaceholderName,
+			Name:      "count",
+			Type:      "int",
+			Format:    "decimalPattern",
 			ExpectedPlaceholders: map[string]*placeholder{
-				"count": {"int", "format"},
+				"count": {"int", "decimalPattern"},
 			},
 		},
 		{
@@ -363,12 +445,12 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 
 	for _, testCase := range cases {
 		t.Run(testCase.TestName, func(t *testing.T) {
-			pc := newTranslationParser(testCase.Plural)
+			pc := newPluralTranslationParser(testCase.CountName)
 			for name, placeholder := range testCase.InitialPlaceholders {
 				pc.namedParams.Set(name, placeholder)
 			}
 
-			err := pc.addPlaceholder(testCase.Name, testCase.Type, testCase.Format)
+			err := pc.addPlaceholderDefinition(testCase.Name, testCase.Type, testCase.Format)
 
 			if testCase.ExpectedError == "" {
 				assert.NoError(t, err)
@@ -389,10 +471,10 @@ func TestTranslationParserAddPlaceholder(t *testing.T) {
 
 func TestTranslationParserFallbackPlaceholderTypes(t *testing.T) {
 	type testCase struct {
-		TestName string
-		Plural   bool
-		Before   map[string]*placeholder
-		After    map[string]*placeholder
+		TestName  string
+		CountName string
+		Before    map[string]*placeholder
+		After     map[string]*placeholder
 	}
 
 	cases := []testCase{
@@ -400,8 +482,8 @@ func TestTranslationParserFallbackPlaceholderTypes(t *testing.T) {
 			TestName: "no placeholders",
 		},
 		{
-			TestName: "no placeholders, plural",
-			Plural:   true,
+			TestName:  "no placeholders, plural",
+			CountName: convert.DefaultCountPlaceholderName,
 			After: map[string]*placeholder{
 				"count": {"", ""},
 			},
@@ -424,8 +506,8 @@ func TestTranslationParserFallbackPlaceholderTypes(t *testing.T) {
 			},
 		},
 		{
-			TestName: "some defined and some seen placeholders, plural",
-			Plural:   true,
+			TestName:  "some defined and some seen placeholders, plural",
+			CountName: convert.DefaultCountPlaceholderName,
 			Before: map[string]*placeholder{
 				"param1": {"String", ""},
 				"param2": nil,
@@ -442,8 +524,8 @@ func TestTranslationParserFallbackPlaceholderTypes(t *testing.T) {
 			},
 		},
 		{
-			TestName: "count defined, plural",
-			Plural:   true,
+			TestName:  "count defined, plural",
+			CountName: convert.DefaultCountPlaceholderName,
 			Before: map[string]*placeholder{
 				"count": {"int", "format"},
 			},
@@ -455,7 +537,7 @@ func TestTranslationParserFallbackPlaceholderTypes(t *testing.T) {
 
 	for _, testCase := range cases {
 		t.Run(testCase.TestName, func(t *testing.T) {
-			pc := newTranslationParser(testCase.Plural)
+			pc := newPluralTranslationParser(testCase.CountName)
 
 			for name, placeholder := range testCase.Before {
 				pc.namedParams.Set(name, placeholder)
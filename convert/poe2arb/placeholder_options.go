@@ -0,0 +1,231 @@
+package poe2arb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// placeholderOptions are the placeholder definition parts other than type and format.
+type placeholderOptions struct {
+	OptionalParameters *orderedmap.OrderedMap[string, any]
+	IsCustomDateFormat bool
+	Example            string
+}
+
+var (
+	placeholderFormatRegexp = regexp.MustCompile(`^([a-zA-Z]+(?:\+[a-zA-Z]+)*)(?:\((.*)\))?$`)
+	optionalParameterRegexp = regexp.MustCompile(`^([a-zA-Z]+)\s*:\s*(.+)$`)
+	examplePrefix           = "example:"
+)
+
+// The formats below are the ones accepted by Flutter gen-l10n (gen_l10n_types.dart).
+
+// numberFormats are the NumberFormat constructors supported by Flutter with their named parameters.
+// The locale parameter is always passed by Flutter.
+var numberFormats = map[string][]string{
+	"compact":               {},
+	"compactCurrency":       {"name", "symbol", "decimalDigits"},
+	"compactSimpleCurrency": {"name", "decimalDigits"},
+	"compactLong":           {},
+	"currency":              {"name", "symbol", "decimalDigits", "customPattern"},
+	"decimalPattern":        nil,
+	"decimalPatternDigits":  {"decimalDigits"},
+	"decimalPercentPattern": {"decimalDigits"},
+	"percentPattern":        nil,
+	"scientificPattern":     nil,
+	"simpleCurrency":        {"name", "decimalDigits"},
+}
+
+// dateFormats are the DateFormat constructors supported by Flutter.
+var dateFormats = []string{
+	"d", "E", "EEEE", "LLL", "LLLL", "M", "Md", "MEd", "MMM", "MMMd", "MMMEd", "MMMM", "MMMMd",
+	"MMMMEEEEd", "QQQ", "QQQQ", "y", "yM", "yMd", "yMEd", "yMMM", "yMMMd", "yMMMEd", "yMMMM",
+	"yMMMMd", "yMMMMEEEEd", "yQQQ", "yQQQQ", "H", "Hm", "Hms", "j", "jm", "jms", "jmv", "jmz",
+	"jv", "jz", "m", "ms", "s",
+}
+
+// parsePlaceholderOptions parses the part of the placeholder definition following its type, e.g.
+//
+//	compactCurrency(decimalDigits: 2, name: "EUR"), example: "€1.2K"
+//	"yyyy-MM-dd"
+func parsePlaceholderOptions(raw string) (format string, options *placeholderOptions, err error) {
+	options = &placeholderOptions{}
+	formatDefined, exampleDefined := false, false
+
+	for _, segment := range splitTopLevel(raw, ',') {
+		segment = strings.TrimSpace(segment)
+
+		switch {
+		case segment == "":
+			continue
+
+		case strings.HasPrefix(segment, examplePrefix):
+			if exampleDefined {
+				return "", nil, errors.New("example can only be defined once")
+			}
+			exampleDefined = true
+
+			options.Example, err = strconv.Unquote(strings.TrimSpace(segment[len(examplePrefix):]))
+			if err != nil {
+				return "", nil, fmt.Errorf("example must be a quoted string: %s", segment)
+			}
+
+		default:
+			if formatDefined {
+				return "", nil, errors.New("format can only be defined once")
+			}
+			formatDefined = true
+
+			if strings.HasPrefix(segment, `"`) {
+				format, err = strconv.Unquote(segment)
+				if err != nil {
+					return "", nil, fmt.Errorf("invalid custom format: %s", segment)
+				}
+				options.IsCustomDateFormat = true
+				continue
+			}
+
+			matches := placeholderFormatRegexp.FindStringSubmatch(segment)
+			if matches == nil {
+				return "", nil, fmt.Errorf("invalid format: %s", segment)
+			}
+
+			format = matches[1]
+			if strings.HasPrefix(segment[len(format):], "(") {
+				options.OptionalParameters, err = parseOptionalParameters(matches[2])
+				if err != nil {
+					return "", nil, err
+				}
+			}
+		}
+	}
+
+	return format, options, nil
+}
+
+// parseOptionalParameters parses named parameters of a format, e.g. decimalDigits: 2, name: "EUR".
+func parseOptionalParameters(raw string) (*orderedmap.OrderedMap[string, any], error) {
+	params := orderedmap.New[string, any]()
+
+	for _, param := range splitTopLevel(raw, ',') {
+		param = strings.TrimSpace(param)
+		if param == "" {
+			continue
+		}
+
+		matches := optionalParameterRegexp.FindStringSubmatch(param)
+		if matches == nil {
+			return nil, fmt.Errorf("invalid format parameter: %s", param)
+		}
+
+		name, rawValue := matches[1], strings.TrimSpace(matches[2])
+		if _, present := params.Get(name); present {
+			return nil, fmt.Errorf("format parameter %s can only be defined once", name)
+		}
+
+		var value any
+		if i, err := strconv.Atoi(rawValue); err == nil {
+			value = i
+		} else if f, err := strconv.ParseFloat(rawValue, 64); err == nil {
+			value = f
+		} else if b, err := strconv.ParseBool(rawValue); err == nil {
+			value = b
+		} else if s, err := strconv.Unquote(rawValue); err == nil {
+			value = s
+		} else {
+			return nil, fmt.Errorf("invalid value of format parameter %s: %s", name, rawValue)
+		}
+
+		params.Set(name, value)
+	}
+
+	return params, nil
+}
+
+// splitTopLevel splits s by sep, ignoring separators in quotes and parentheses.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+
+	depth, inQuotes, escaped, start := 0, false, false, 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case inQuotes && r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + len(string(sep))
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// validateNumberFormat checks if the format and its parameters are supported by Flutter.
+func validateNumberFormat(format string, options *placeholderOptions) error {
+	if options.IsCustomDateFormat {
+		return errors.New("custom format is supported only for DateTime placeholders")
+	}
+
+	if format == "" {
+		if options.OptionalParameters != nil {
+			return errors.New("format parameters require a format")
+		}
+		return nil
+	}
+
+	allowedParams, ok := numberFormats[format]
+	if !ok {
+		return fmt.Errorf("unknown number format %s", format)
+	}
+
+	if options.OptionalParameters == nil {
+		return nil
+	}
+
+	if allowedParams == nil {
+		return fmt.Errorf("number format %s does not accept parameters", format)
+	}
+
+	for pair := options.OptionalParameters.Oldest(); pair != nil; pair = pair.Next() {
+		if !slices.Contains(allowedParams, pair.Key) {
+			return fmt.Errorf("unknown parameter %s of number format %s", pair.Key, format)
+		}
+	}
+
+	return nil
+}
+
+// validateDateFormat checks if the format is supported by Flutter.
+// Formats can be combined with a plus sign, e.g. yMd+jms.
+func validateDateFormat(format string, options *placeholderOptions) error {
+	if options.OptionalParameters != nil {
+		return errors.New("format parameters are not supported for DateTime placeholders")
+	}
+
+	if options.IsCustomDateFormat {
+		return nil
+	}
+
+	for _, part := range strings.Split(format, "+") {
+		if !slices.Contains(dateFormats, part) {
+			return fmt.Errorf(`unknown date format %s, use a quoted pattern for custom date formats, e.g. "yyyy-MM-dd"`, part)
+		}
+	}
+
+	return nil
+}
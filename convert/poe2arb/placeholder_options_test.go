@@ -0,0 +1,89 @@
+package poe2arb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+func TestParsePlaceholderOptions(t *testing.T) {
+	params := func(pairs ...orderedmap.Pair[string, any]) *orderedmap.OrderedMap[string, any] {
+		return orderedmap.New[string, any](orderedmap.WithInitialData(pairs...))
+	}
+
+	type testCase struct {
+		Input           string
+		ExpectedFormat  string
+		ExpectedOptions *placeholderOptions
+		ExpectedError   string
+	}
+
+	cases := []testCase{
+		{
+			Input:           "",
+			ExpectedOptions: &placeholderOptions{},
+		},
+		{
+			Input:           "yMd+jms",
+			ExpectedFormat:  "yMd+jms",
+			ExpectedOptions: &placeholderOptions{},
+		},
+		{
+			Input:          `compactCurrency(decimalDigits: 2, name: "EUR, \"euro\""), example: "€1.2K"`,
+			ExpectedFormat: "compactCurrency",
+			ExpectedOptions: &placeholderOptions{
+				OptionalParameters: params(
+					orderedmap.Pair[string, any]{Key: "decimalDigits", Value: 2},
+					orderedmap.Pair[string, any]{Key: "name", Value: `EUR, "euro"`},
+				),
+				Example: "€1.2K",
+			},
+		},
+		{
+			Input:          "compact()",
+			ExpectedFormat: "compact",
+			ExpectedOptions: &placeholderOptions{
+				OptionalParameters: params(),
+			},
+		},
+		{
+			Input:          `"EEE, M/d/y"`,
+			ExpectedFormat: "EEE, M/d/y",
+			ExpectedOptions: &placeholderOptions{
+				IsCustomDateFormat: true,
+			},
+		},
+		{
+			Input:         "yMd,jms",
+			ExpectedError: "format can only be defined once",
+		},
+		{
+			Input:         "example: Bob",
+			ExpectedError: "example must be a quoted string: example: Bob",
+		},
+		{
+			Input:         "currency(name: EUR)",
+			ExpectedError: "invalid value of format parameter name: EUR",
+		},
+		{
+			Input:         "currency(decimalDigits: 1, decimalDigits: 2)",
+			ExpectedError: "format parameter decimalDigits can only be defined once",
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.Input, func(t *testing.T) {
+			format, options, err := parsePlaceholderOptions(testCase.Input)
+
+			if testCase.ExpectedError != "" {
+				assert.EqualError(t, err, testCase.ExpectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.ExpectedFormat, format)
+			assert.Equal(t, testCase.ExpectedOptions, options)
+		})
+	}
+}
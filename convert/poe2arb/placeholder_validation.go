@@ -0,0 +1,72 @@
+package poe2arb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PlaceholderMismatchMode decides what happens to non-template messages whose
+// placeholders differ from the template ones.
+type PlaceholderMismatchMode string
+
+const (
+	// PlaceholderMismatchWarn keeps the message, the mismatch is only reported.
+	PlaceholderMismatchWarn PlaceholderMismatchMode = "warn"
+	// PlaceholderMismatchSkip leaves the message out of the ARB file,
+	// so that Flutter falls back to the template translation.
+	PlaceholderMismatchSkip PlaceholderMismatchMode = "skip"
+	// PlaceholderMismatchFail fails the conversion.
+	PlaceholderMismatchFail PlaceholderMismatchMode = "fail"
+)
+
+var PlaceholderMismatchModes = []PlaceholderMismatchMode{
+	PlaceholderMismatchWarn,
+	PlaceholderMismatchSkip,
+	PlaceholderMismatchFail,
+}
+
+// PlaceholderMismatch describes a message whose placeholders differ from the template.
+type PlaceholderMismatch struct {
+	Message string
+	// Missing are template placeholders not used in the translation.
+	Missing []string
+	// Unknown are placeholders used in the translation, but not defined in the template.
+	Unknown []string
+}
+
+func (m *PlaceholderMismatch) Error() string {
+	var parts []string
+	if len(m.Missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(m.Missing, ", "))
+	}
+	if len(m.Unknown) > 0 {
+		parts = append(parts, "unknown "+strings.Join(m.Unknown, ", "))
+	}
+
+	return fmt.Sprintf("%s: placeholders differ from template: %s", m.Message, strings.Join(parts, "; "))
+}
+
+// comparePlaceholders returns the mismatch between the template placeholders
+// and the ones used in the translation, or nil if they are the same.
+func comparePlaceholders(message string, template, used []string) *PlaceholderMismatch {
+	mismatch := &PlaceholderMismatch{Message: message}
+
+	for _, name := range template {
+		if !slices.Contains(used, name) {
+			mismatch.Missing = append(mismatch.Missing, name)
+		}
+	}
+
+	for _, name := range used {
+		if !slices.Contains(template, name) {
+			mismatch.Unknown = append(mismatch.Unknown, name)
+		}
+	}
+
+	if len(mismatch.Missing) == 0 && len(mismatch.Unknown) == 0 {
+		return nil
+	}
+
+	return mismatch
+}
@@ -0,0 +1,100 @@
+package poe2arb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
+)
+
+// IncompletePluralMode decides what happens to plural messages missing
+// some of the plural categories required by the language.
+type IncompletePluralMode string
+
+const (
+	// IncompletePluralWarn keeps the message, the missing categories are only reported.
+	IncompletePluralWarn IncompletePluralMode = "warn"
+	// IncompletePluralSkip leaves the message out of the ARB file.
+	IncompletePluralSkip IncompletePluralMode = "skip"
+	// IncompletePluralFail fails the conversion.
+	IncompletePluralFail IncompletePluralMode = "fail"
+)
+
+var IncompletePluralModes = []IncompletePluralMode{
+	IncompletePluralWarn,
+	IncompletePluralSkip,
+	IncompletePluralFail,
+}
+
+// IncompletePlural describes a plural message missing some of the language's plural categories.
+type IncompletePlural struct {
+	Message string
+	Missing []string
+}
+
+func (p *IncompletePlural) Error() string {
+	return fmt.Sprintf("%s: missing plural categories: %s", p.Message, strings.Join(p.Missing, ", "))
+}
+
+// checkPluralCategories returns the categories required by the language, but missing
+// or empty in the plural, or nil if the plural is complete.
+func checkPluralCategories(message string, plural *convert.POETermPluralDefinition, categories []string) *IncompletePlural {
+	forms := map[string]*string{
+		"zero":  plural.Zero,
+		"one":   plural.One,
+		"two":   plural.Two,
+		"few":   plural.Few,
+		"many":  plural.Many,
+		"other": &plural.Other,
+	}
+
+	incomplete := &IncompletePlural{Message: message}
+	for _, category := range categories {
+		if form := forms[category]; form == nil || *form == "" {
+			incomplete.Missing = append(incomplete.Missing, category)
+		}
+	}
+
+	if len(incomplete.Missing) == 0 {
+		return nil
+	}
+
+	return incomplete
+}
+
+// checkMessagePluralCategories returns the categories required by the language, but missing
+// or empty in any plural of the ICU message, e.g. a plural inside a text term,
+// or nil if all of its plurals are complete.
+func checkMessagePluralCategories(message string, m icu.Message, categories []string) *IncompletePlural {
+	missing := map[string]bool{}
+	icu.Walk(m, func(node icu.Node) {
+		plural, ok := node.(*icu.Plural)
+		if !ok {
+			return
+		}
+
+		for _, category := range categories {
+			hasCase := slices.ContainsFunc(plural.Cases, func(c *icu.Case) bool {
+				return c.Key == category && len(c.Message) > 0
+			})
+			if !hasCase {
+				missing[category] = true
+			}
+		}
+	})
+
+	incomplete := &IncompletePlural{Message: message}
+	for _, category := range categories {
+		if missing[category] {
+			incomplete.Missing = append(incomplete.Missing, category)
+		}
+	}
+
+	if len(incomplete.Missing) == 0 {
+		return nil
+	}
+
+	return incomplete
+}
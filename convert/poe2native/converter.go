@@ -0,0 +1,257 @@
+// Package poe2native handles conversion from POEditor's JSON to native iOS and Android strings files.
+package poe2native
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert"
+)
+
+// Platform is a native platform strings are converted for.
+type Platform string
+
+const (
+	// PlatformIOS writes InfoPlist.strings files.
+	PlatformIOS Platform = "ios"
+	// PlatformAndroid writes strings.xml resource files.
+	PlatformAndroid Platform = "android"
+)
+
+var (
+	prefixedTermRegexp    = regexp.MustCompile("(?:([a-zA-Z]+):)?(.*)")
+	androidNameRegexp     = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+	errPluralsUnsupported = errors.New("plurals are not supported in native strings")
+)
+
+type Converter struct {
+	input io.Reader
+
+	platform   Platform
+	termPrefix string
+	existing   []byte
+}
+
+type ConverterOptions struct {
+	Platform Platform
+	// TermPrefix selects the terms converted to native strings. Required.
+	TermPrefix string
+	// Existing is the current strings file, empty if there's none. Unless it was generated
+	// by poe2arb, the translated strings are merged into it, keeping the other strings.
+	Existing []byte
+}
+
+func NewConverter(input io.Reader, options *ConverterOptions) *Converter {
+	return &Converter{
+		input: input,
+
+		platform:   options.Platform,
+		termPrefix: options.TermPrefix,
+		existing:   options.Existing,
+	}
+}
+
+// nativeString is a single translated string.
+type nativeString struct {
+	Key   string
+	Value string
+}
+
+// Convert writes translated terms with the term prefix in the platform's format.
+// Untranslated terms are left out, so that the platform falls back to its defaults.
+func (c *Converter) Convert(output io.Writer) error {
+	var jsonContents []*convert.POETerm
+	err := json.NewDecoder(c.input).Decode(&jsonContents)
+	if err != nil {
+		return fmt.Errorf("decoding json failed: %w", err)
+	}
+
+	var strs []nativeString
+	var errs []error
+	for _, term := range jsonContents {
+		matches := prefixedTermRegexp.FindStringSubmatch(term.Term)
+		if matches[1] != c.termPrefix {
+			continue
+		}
+		key := matches[2]
+
+		if term.Definition.IsPlural {
+			errs = append(errs, fmt.Errorf(`term "%s": %w`, key, errPluralsUnsupported))
+			continue
+		}
+
+		if c.platform == PlatformAndroid && !androidNameRegexp.MatchString(key) {
+			errs = append(errs, fmt.Errorf(`term "%s": invalid Android resource name`, key))
+			continue
+		}
+
+		if term.Definition.Value == nil || *term.Definition.Value == "" {
+			continue
+		}
+
+		strs = append(strs, nativeString{Key: key, Value: *term.Definition.Value})
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	slices.SortStableFunc(strs, func(a, b nativeString) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	if len(c.existing) > 0 && !bytes.Contains(c.existing, []byte(generatedComment)) {
+		return mergeStrings(output, c.platform, string(c.existing), strs)
+	}
+
+	switch c.platform {
+	case PlatformIOS:
+		return writeIOSStrings(output, strs)
+	case PlatformAndroid:
+		return writeAndroidStrings(output, strs)
+	default:
+		return fmt.Errorf("unknown platform %q", c.platform)
+	}
+}
+
+const generatedComment = "Generated by poe2arb from POEditor terms, do not edit."
+
+func writeIOSStrings(w io.Writer, strs []nativeString) error {
+	var sb strings.Builder
+	sb.WriteString("/* " + generatedComment + " */\n")
+	for _, str := range strs {
+		sb.WriteString(iosLine(str))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func iosLine(str nativeString) string {
+	return fmt.Sprintf("\"%s\" = \"%s\";\n", escapeIOS(str.Key), escapeIOS(str.Value))
+}
+
+var iosReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeIOS(s string) string {
+	return iosReplacer.Replace(s)
+}
+
+func writeAndroidStrings(w io.Writer, strs []nativeString) error {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	sb.WriteString("<!-- " + generatedComment + " -->\n")
+	sb.WriteString("<resources>\n")
+	for _, str := range strs {
+		sb.WriteString(androidLine(str))
+	}
+	sb.WriteString("</resources>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func androidLine(str nativeString) string {
+	return fmt.Sprintf("    <string name=\"%s\">%s</string>\n", str.Key, escapeAndroid(str.Value))
+}
+
+var androidReplacer = strings.NewReplacer(
+	`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`,
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+)
+
+// escapeAndroid escapes the XML special characters along with the ones
+// interpreted by Android resources.
+func escapeAndroid(s string) string {
+	s = androidReplacer.Replace(s)
+
+	// Leading @ and ? would reference other resources.
+	if strings.HasPrefix(s, "@") || strings.HasPrefix(s, "?") {
+		s = `\` + s
+	}
+
+	return s
+}
+
+var (
+	iosStringLineRegexp     = regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*=\s*"(?:[^"\\]|\\.)*"\s*;\s*$`)
+	androidStringLineRegexp = regexp.MustCompile(`^\s*<string name="([^"]*)"[^>]*>[^<]*</string>\s*$`)
+
+	// iosStringStartRegexp and androidStringStartRegexp match the start of a string
+	// continued in the following lines.
+	iosStringStartRegexp     = regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*=`)
+	androidStringStartRegexp = regexp.MustCompile(`^\s*<string name="([^"]*)"`)
+)
+
+// mergeStrings writes the existing strings file with the translated strings replaced
+// or, if missing, added at its end. Other strings and comments are kept as they are.
+// Only strings written in a single line can be replaced.
+func mergeStrings(w io.Writer, platform Platform, existing string, strs []nativeString) error {
+	var lineRegexp, startRegexp *regexp.Regexp
+	var formatLine func(nativeString) string
+	// escapeKey returns the key as written in the file.
+	var escapeKey func(key string) string
+	switch platform {
+	case PlatformIOS:
+		lineRegexp, startRegexp, formatLine, escapeKey = iosStringLineRegexp, iosStringStartRegexp, iosLine, escapeIOS
+	case PlatformAndroid:
+		lineRegexp, startRegexp, formatLine = androidStringLineRegexp, androidStringStartRegexp, androidLine
+		escapeKey = func(key string) string { return key }
+	default:
+		return fmt.Errorf("unknown platform %q", platform)
+	}
+
+	byKey := map[string]nativeString{}
+	for _, str := range strs {
+		byKey[escapeKey(str.Key)] = str
+	}
+
+	var lines []string
+	merged := map[string]bool{}
+	for _, line := range strings.SplitAfter(existing, "\n") {
+		if matches := lineRegexp.FindStringSubmatch(line); matches != nil {
+			if str, ok := byKey[matches[1]]; ok {
+				lines = append(lines, formatLine(str))
+				merged[str.Key] = true
+				continue
+			}
+		} else if matches := startRegexp.FindStringSubmatch(line); matches != nil {
+			if str, ok := byKey[matches[1]]; ok {
+				return fmt.Errorf(`string "%s" must be written in a single line to be updated`, str.Key)
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	var missing []string
+	for _, str := range strs {
+		if !merged[str.Key] {
+			missing = append(missing, formatLine(str))
+		}
+	}
+
+	if last := len(lines) - 1; lines[last] != "" && !strings.HasSuffix(lines[last], "\n") {
+		lines[last] += "\n"
+	}
+
+	insertAt := len(lines)
+	if platform == PlatformAndroid {
+		insertAt = slices.IndexFunc(lines, func(line string) bool {
+			return strings.HasPrefix(strings.TrimSpace(line), "</resources>")
+		})
+		if insertAt == -1 {
+			return errors.New("missing </resources> closing tag")
+		}
+	}
+	lines = slices.Insert(lines, insertAt, missing...)
+
+	_, err := io.WriteString(w, strings.Join(lines, ""))
+	return err
+}
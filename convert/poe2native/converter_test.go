@@ -0,0 +1,189 @@
+package poe2native_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert/poe2native"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConverterConvert(t *testing.T) {
+	source := `[
+		{"term": "ios:NSCameraUsageDescription", "definition": "Scan \"cards\" with a camera\nor type them", "term_plural": ""},
+		{"term": "ios:CFBundleDisplayName", "definition": "Bank", "term_plural": ""},
+		{"term": "ios:NSLocationUsageDescription", "definition": "", "term_plural": ""},
+		{"term": "android:app_name", "definition": "Bank & Co's", "term_plural": ""},
+		{"term": "android:handle", "definition": "@bank <1>", "term_plural": ""},
+		{"term": "appTitle", "definition": "Bank", "term_plural": ""}
+	]`
+
+	type testCase struct {
+		Platform   poe2native.Platform
+		TermPrefix string
+		Expected   string
+	}
+
+	testCases := []testCase{
+		{
+			Platform:   poe2native.PlatformIOS,
+			TermPrefix: "ios",
+			Expected: `/* Generated by poe2arb from POEditor terms, do not edit. */
+"CFBundleDisplayName" = "Bank";
+"NSCameraUsageDescription" = "Scan \"cards\" with a camera\nor type them";
+`,
+		},
+		{
+			Platform:   poe2native.PlatformAndroid,
+			TermPrefix: "android",
+			Expected: `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by poe2arb from POEditor terms, do not edit. -->
+<resources>
+    <string name="app_name">Bank &amp; Co\'s</string>
+    <string name="handle">\@bank &lt;1&gt;</string>
+</resources>
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.Platform), func(t *testing.T) {
+			conv := poe2native.NewConverter(strings.NewReader(source), &poe2native.ConverterOptions{
+				Platform:   testCase.Platform,
+				TermPrefix: testCase.TermPrefix,
+			})
+
+			var b bytes.Buffer
+			err := conv.Convert(&b)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, b.String())
+		})
+	}
+}
+
+func TestConverterErrors(t *testing.T) {
+	source := `[
+		{"term": "android:app-name", "definition": "Bank", "term_plural": ""},
+		{"term": "android:cards", "definition": {"one": "card", "other": "cards"}, "term_plural": "count"}
+	]`
+
+	conv := poe2native.NewConverter(strings.NewReader(source), &poe2native.ConverterOptions{
+		Platform:   poe2native.PlatformAndroid,
+		TermPrefix: "android",
+	})
+	err := conv.Convert(new(bytes.Buffer))
+
+	assert.EqualError(t, err, `term "app-name": invalid Android resource name`+"\n"+
+		`term "cards": plurals are not supported in native strings`)
+}
+
+func TestConverterMerge(t *testing.T) {
+	source := `[
+		{"term": "ios:CFBundleDisplayName", "definition": "Bank", "term_plural": ""},
+		{"term": "ios:NSCameraUsageDescription", "definition": "Scan cards", "term_plural": ""},
+		{"term": "android:app_name", "definition": "Bank", "term_plural": ""},
+		{"term": "android:title", "definition": "Cards", "term_plural": ""}
+	]`
+
+	type testCase struct {
+		Platform   poe2native.Platform
+		TermPrefix string
+		Existing   string
+		Expected   string
+	}
+
+	testCases := []testCase{
+		{
+			Platform:   poe2native.PlatformIOS,
+			TermPrefix: "ios",
+			Existing: `/* Maintained by hand */
+"NSPhotoLibraryUsageDescription" = "Pick a photo";
+"CFBundleDisplayName" = "Old name";`,
+			Expected: `/* Maintained by hand */
+"NSPhotoLibraryUsageDescription" = "Pick a photo";
+"CFBundleDisplayName" = "Bank";
+"NSCameraUsageDescription" = "Scan cards";
+`,
+		},
+		{
+			Platform:   poe2native.PlatformAndroid,
+			TermPrefix: "android",
+			Existing: `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name">Old name</string>
+    <!-- Maintained by hand -->
+    <string name="channel_id" translatable="false">payments</string>
+    <string name="terms">
+        Accept the terms
+    </string>
+</resources>
+`,
+			Expected: `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name">Bank</string>
+    <!-- Maintained by hand -->
+    <string name="channel_id" translatable="false">payments</string>
+    <string name="terms">
+        Accept the terms
+    </string>
+    <string name="title">Cards</string>
+</resources>
+`,
+		},
+		{
+			Platform:   poe2native.PlatformAndroid,
+			TermPrefix: "android",
+			Existing: `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by poe2arb from POEditor terms, do not edit. -->
+<resources>
+    <string name="removed">Removed</string>
+</resources>
+`,
+			Expected: `<?xml version="1.0" encoding="utf-8"?>
+<!-- Generated by poe2arb from POEditor terms, do not edit. -->
+<resources>
+    <string name="app_name">Bank</string>
+    <string name="title">Cards</string>
+</resources>
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.Platform), func(t *testing.T) {
+			conv := poe2native.NewConverter(strings.NewReader(source), &poe2native.ConverterOptions{
+				Platform:   testCase.Platform,
+				TermPrefix: testCase.TermPrefix,
+				Existing:   []byte(testCase.Existing),
+			})
+
+			var b bytes.Buffer
+			err := conv.Convert(&b)
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.Expected, b.String())
+		})
+	}
+}
+
+func TestConverterMergeErrors(t *testing.T) {
+	source := `[{"term": "android:app_name", "definition": "Bank", "term_plural": ""}]`
+
+	testCases := map[string]string{
+		"<resources>\n    <string name=\"app_name\">\n        Old name\n    </string>\n</resources>\n": `string "app_name" must be written in a single line to be updated`,
+		"<resources>\n": "missing </resources> closing tag",
+	}
+
+	for existing, expectedErr := range testCases {
+		conv := poe2native.NewConverter(strings.NewReader(source), &poe2native.ConverterOptions{
+			Platform:   poe2native.PlatformAndroid,
+			TermPrefix: "android",
+			Existing:   []byte(existing),
+		})
+		err := conv.Convert(new(bytes.Buffer))
+
+		assert.EqualError(t, err, expectedErr)
+	}
+}
@@ -0,0 +1,175 @@
+// Package pseudo generates pseudo-localized ARB files from the template ones, so that
+// untranslated, hard-coded and truncated strings are easy to spot.
+package pseudo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/flutter"
+	orderedmap "github.com/wk8/go-ordered-map/v2"
+)
+
+// DefaultLocale is the pseudo-locale used by Android for accented English.
+const DefaultLocale = "en_XA"
+
+// DefaultPadding is the default percentage messages are lengthened by.
+const DefaultPadding = 30
+
+const (
+	startMarker = "["
+	endMarker   = "]"
+	paddingChar = "~"
+)
+
+var accents = func() map[rune]rune {
+	plain := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	accented := []rune("åƀçđéƒĝĥîĵķļɱñöþǫŕšţûṽŵẋýžÅƁÇĐÉƑĜĤÎĴĶĻṀÑÖÞǪŔŠŢÛṼŴẊÝŽ")
+
+	m := map[rune]rune{}
+	for i, r := range plain {
+		m[r] = accented[i]
+	}
+	return m
+}()
+
+type Converter struct {
+	input io.Reader
+
+	locale      flutter.Locale
+	padding     int
+	useEscaping bool
+}
+
+type ConverterOptions struct {
+	// Locale of the generated ARB file, e.g. en_XA.
+	Locale flutter.Locale
+	// Padding is the percentage messages are lengthened by, e.g. 30 adds 3 characters to 10 characters long text.
+	Padding int
+	// UseEscaping enables ICU quoting with apostrophes, the same as gen-l10n use-escaping option.
+	UseEscaping bool
+}
+
+func NewConverter(input io.Reader, options *ConverterOptions) *Converter {
+	return &Converter{
+		input: input,
+
+		locale:      options.Locale,
+		padding:     options.Padding,
+		useEscaping: options.UseEscaping,
+	}
+}
+
+// Convert reads the template ARB and writes its messages pseudo-localized. Placeholders,
+// plurals and selects are kept intact, only the text is changed.
+func (c *Converter) Convert(output io.Writer) error {
+	template := orderedmap.New[string, any]()
+	if err := json.NewDecoder(c.input).Decode(&template); err != nil {
+		return fmt.Errorf("decoding ARB failed: %w", err)
+	}
+
+	arb := orderedmap.New[string, any]()
+	arb.Set(convert.LocaleKey, c.locale.String())
+
+	var errs []error
+	for pair := template.Oldest(); pair != nil; pair = pair.Next() {
+		if strings.HasPrefix(pair.Key, "@") {
+			continue
+		}
+
+		translation, ok := pair.Value.(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: translation is not a string", pair.Key))
+			continue
+		}
+
+		localized, err := c.localize(translation)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pair.Key, err))
+			continue
+		}
+
+		arb.Set(pair.Key, localized)
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ") // 4 spaces
+
+	if err := encoder.Encode(arb); err != nil {
+		return fmt.Errorf("encoding arb failed: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Converter) localize(translation string) (string, error) {
+	options := icu.Options{Escaping: c.useEscaping, Relaxed: true}
+
+	message, err := icu.Parse(translation, options)
+	if err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+
+	return icu.Print(c.wrap(accentMessage(message)), options), nil
+}
+
+// wrap pads the message and wraps it with markers. A message being a single plural
+// or select has its cases wrapped instead, as gen-l10n expects it to be the whole message.
+func (c *Converter) wrap(m icu.Message) icu.Message {
+	if len(m) == 1 {
+		var cases []*icu.Case
+		switch n := m[0].(type) {
+		case *icu.Plural:
+			cases = n.Cases
+		case *icu.Select:
+			cases = n.Cases
+		}
+
+		if cases != nil {
+			for _, cs := range cases {
+				cs.Message = c.wrap(cs.Message)
+			}
+			return m
+		}
+	}
+
+	length := 0
+	icu.Walk(m, func(node icu.Node) {
+		if text, ok := node.(*icu.Text); ok {
+			length += utf8.RuneCountInString(text.Value)
+		}
+	})
+	padding := strings.Repeat(paddingChar, int(math.Ceil(float64(length*c.padding)/100)))
+
+	wrapped := icu.Message{&icu.Text{Value: startMarker}}
+	wrapped = append(wrapped, m...)
+	return append(wrapped, &icu.Text{Value: padding + endMarker})
+}
+
+// accentMessage replaces letters of the text with their accented versions.
+func accentMessage(m icu.Message) icu.Message {
+	icu.Walk(m, func(node icu.Node) {
+		if text, ok := node.(*icu.Text); ok {
+			text.Value = strings.Map(func(r rune) rune {
+				if accented, ok := accents[r]; ok {
+					return accented
+				}
+				return r
+			}, text.Value)
+		}
+	})
+
+	return m
+}
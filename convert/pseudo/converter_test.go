@@ -0,0 +1,66 @@
+package pseudo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert/pseudo"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConverterConvert(t *testing.T) {
+	type testCase struct {
+		Name        string
+		Translation string
+		Padding     int
+		UseEscaping bool
+		Expected    string
+	}
+
+	testCases := []testCase{
+		{"text", "Hello", 30, false, "[Ĥéļļö~~]"},
+		{"no padding", "Hello", 0, false, "[Ĥéļļö]"},
+		{"placeholder", "Hi, {name}!", 100, false, "[Ĥî, {name}!~~~~~]"},
+		{
+			"plural", "{count, plural, =0 {No items} one {{count} item} other {{count} items}}", 50, false,
+			"{count, plural, =0 {[Ñö îţéɱš~~~~]} one {[{count} îţéɱ~~~]} other {[{count} îţéɱš~~~]}}",
+		},
+		{
+			"select nested in text", "Hi {gender, select, male {him} other {them}}", 0, false,
+			"[Ĥî {gender, select, male {ĥîɱ} other {ţĥéɱ}}]",
+		},
+		{"escaping", "Use '{braces}' like it''s {name}", 0, true, "[Ûšé '{ƀŕåçéš}' ļîķé îţ''š {name}]"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			input := `{"@@locale": "en", "message": "` + testCase.Translation + `", "@message": {"description": "Test"}}`
+
+			conv := pseudo.NewConverter(strings.NewReader(input), &pseudo.ConverterOptions{
+				Locale:      flutter.Locale{Language: "en", Country: "XA"},
+				Padding:     testCase.Padding,
+				UseEscaping: testCase.UseEscaping,
+			})
+
+			var b bytes.Buffer
+			err := conv.Convert(&b)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "{\n    \"@@locale\": \"en_XA\",\n    \"message\": \""+testCase.Expected+"\"\n}\n", b.String())
+		})
+	}
+}
+
+func TestConverterErrors(t *testing.T) {
+	input := `{"@@locale": "en", "broken": "{count, plural, one {item}", "number": 1}`
+
+	conv := pseudo.NewConverter(strings.NewReader(input), &pseudo.ConverterOptions{
+		Locale: flutter.Locale{Language: "en", Country: "XA"},
+	})
+	err := conv.Convert(new(bytes.Buffer))
+
+	assert.EqualError(t, err, "broken: invalid message: unterminated argument at offset 0\n"+
+		"number: translation is not a string")
+}
@@ -5,9 +5,11 @@ package flutter
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -27,13 +29,43 @@ type L10n struct {
 	ARBDir                    string `yaml:"arb-dir"`
 	TemplateArbFile           string `yaml:"template-arb-file"`
 	RequireResourceAttributes bool   `yaml:"required-resource-attributes"`
+	UseEscaping               bool   `yaml:"use-escaping"`
+	OutputLocalizationFile    string `yaml:"output-localization-file"`
+	OutputClass               string `yaml:"output-class"`
 
 	// custom options
 
-	POEditorProjectID  string   `yaml:"poeditor-project-id"`
-	POEditorLangs      []string `yaml:"poeditor-langs"`
-	POEditorTermPrefix string   `yaml:"poeditor-term-prefix"`
-	Poe2ArbVersion     string   `yaml:"poe2arb-version"`
+	POEditorProjectID           string   `yaml:"poeditor-project-id"`
+	POEditorLangs               []string `yaml:"poeditor-langs"`
+	POEditorTermPrefix          string   `yaml:"poeditor-term-prefix"`
+	POEditorNaming              string   `yaml:"poeditor-naming"`
+	POEditorConcurrency         int      `yaml:"poeditor-concurrency"`
+	POEditorPlaceholderMismatch string   `yaml:"poeditor-placeholder-mismatch"`
+	POEditorIncompletePlurals   string   `yaml:"poeditor-incomplete-plurals"`
+	POEditorMinCoverage         float64  `yaml:"poeditor-min-coverage"`
+	POEditorLowCoverage         string   `yaml:"poeditor-low-coverage"`
+	POEditorIOSTermPrefix       string   `yaml:"poeditor-ios-term-prefix"`
+	POEditorIOSDir              string   `yaml:"poeditor-ios-dir"`
+	POEditorAndroidTermPrefix   string   `yaml:"poeditor-android-term-prefix"`
+	POEditorAndroidResDir       string   `yaml:"poeditor-android-res-dir"`
+	POEditorSyncPlatformLocales bool     `yaml:"poeditor-sync-platform-locales"`
+	POEditorStrictSyntax        bool     `yaml:"poeditor-strict-syntax"`
+	POEditorPseudoLocale        string   `yaml:"poeditor-pseudo-locale"`
+	POEditorUsagePatterns       []string `yaml:"poeditor-usage-patterns"`
+	Poe2ArbVersion              string   `yaml:"poe2arb-version"`
+
+	// POEditorPseudoPadding is nil if not set, as 0 disables the padding.
+	POEditorPseudoPadding *int `yaml:"poeditor-pseudo-padding"`
+
+	// POEditorFlavors are flavors by their names.
+	POEditorFlavors map[string]*L10nFlavor `yaml:"poeditor-flavors"`
+}
+
+// L10nFlavor represents a flavor in the l10n.yaml, exported with its term prefixes
+// layered on top of the poeditor-term-prefix.
+type L10nFlavor struct {
+	TermPrefixes []string `yaml:"term-prefixes"`
+	ARBDir       string   `yaml:"arb-dir"`
 }
 
 func newDefaultL10n() *L10n {
@@ -41,6 +73,10 @@ func newDefaultL10n() *L10n {
 		ARBDir:                    "lib/l10n",
 		TemplateArbFile:           "app_en.arb",
 		RequireResourceAttributes: false,
+		OutputLocalizationFile:    "app_localizations.dart",
+		OutputClass:               "AppLocalizations",
+		POEditorIOSDir:            "ios/Runner",
+		POEditorAndroidResDir:     "android/app/src/main/res",
 	}
 }
 
@@ -55,8 +91,50 @@ func NewFromDirectory(dir string) (*FlutterConfig, error) {
 		return nil, ErrNoPubspec
 	}
 
-	rootDir := filepath.Dir(pubspec.Name())
+	return newFromRootDir(filepath.Dir(pubspec.Name()))
+}
+
+// FindPackages returns configurations of all Flutter packages with l10n.yaml
+// inside the given directory, including itself. Hidden and build directories are skipped.
+func FindPackages(dir string) ([]*FlutterConfig, error) {
+	var configs []*FlutterConfig
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		if path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "build") {
+			return filepath.SkipDir
+		}
+
+		for _, name := range []string{"pubspec.yaml", "l10n.yaml"} {
+			if _, err := os.Stat(filepath.Join(path, name)); errors.Is(err, os.ErrNotExist) {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failure searching for %s: %w", name, err)
+			}
+		}
+
+		cfg, err := newFromRootDir(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		configs = append(configs, cfg)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
 
+func newFromRootDir(rootDir string) (*FlutterConfig, error) {
 	l10n := newDefaultL10n()
 	l10nFile, err := getL10nFile(rootDir)
 	if err != nil {
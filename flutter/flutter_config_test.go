@@ -78,3 +78,42 @@ func TestNewFromDirectory(t *testing.T) {
 		assert.Equal(t, ">=0.5.0, <0.7", cfg.L10n.Poe2ArbVersion)
 	})
 }
+
+func TestFindPackages(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"pubspec.yaml":                       "",
+		"packages/loans/pubspec.yaml":        "",
+		"packages/loans/l10n.yaml":           "{poeditor-term-prefix: loans}",
+		"packages/cards/pubspec.yaml":        "",
+		"packages/cards/l10n.yaml":           "{poeditor-term-prefix: cards, arb-dir: lib/src/l10n}",
+		"packages/no_l10n/pubspec.yaml":      "",
+		"packages/cards/build/l10n.yaml":     "",
+		"packages/cards/build/pubspec.yaml":  "",
+		".dart_tool/hidden/pubspec.yaml":     "",
+		".dart_tool/hidden/l10n.yaml":        "",
+		"packages/cards/example/l10n.yaml":   "",
+		"packages/cards/example/README.md":   "",
+		"packages/design/lib/pubspec.yaml":   "",
+		"packages/design/lib/l10n/l10n.yaml": "",
+	}
+	for name, contents := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o777))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o666))
+	}
+
+	cfgs, err := flutter.FindPackages(root)
+
+	assert.NoError(t, err)
+	if assert.Len(t, cfgs, 2) {
+		assert.Equal(t, filepath.Join(root, "packages/cards"), cfgs[0].RootDir)
+		assert.Equal(t, "cards", cfgs[0].L10n.POEditorTermPrefix)
+		assert.Equal(t, "lib/src/l10n", cfgs[0].L10n.ARBDir)
+
+		assert.Equal(t, filepath.Join(root, "packages/loans"), cfgs[1].RootDir)
+		assert.Equal(t, "loans", cfgs[1].L10n.POEditorTermPrefix)
+		assert.Equal(t, "lib/l10n", cfgs[1].L10n.ARBDir)
+	}
+}
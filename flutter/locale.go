@@ -85,3 +85,44 @@ func (l Locale) StringHyphen() string {
 	}
 	return strings.ToLower(locale)
 }
+
+// LanguageTag returns the BCP 47 language tag, e.g. "pt-BR" or "zh-Hant-TW",
+// used by iOS and Android to declare supported locales.
+func (l Locale) LanguageTag() string {
+	locale := strings.ToLower(l.Language)
+	if l.Script != "" {
+		locale += "-" + l.Script
+	}
+	if l.Country != "" {
+		locale += "-" + l.Country
+	}
+	return locale
+}
+
+// IOSDirName returns the name of the iOS localization directory, e.g. "pt-BR.lproj".
+func (l Locale) IOSDirName() string {
+	return l.LanguageTag() + ".lproj"
+}
+
+// AndroidDirName returns the name of the Android resources directory, e.g. "values-pt-rBR".
+// Locales with a script or a numeric region use the BCP 47 form, e.g. "values-b+zh+Hant+TW".
+func (l Locale) AndroidDirName() string {
+	language := strings.ToLower(l.Language)
+
+	if l.Script != "" || (l.Country != "" && len(l.Country) != 2) {
+		dir := "values-b+" + language
+		if l.Script != "" {
+			dir += "+" + l.Script
+		}
+		if l.Country != "" {
+			dir += "+" + l.Country
+		}
+		return dir
+	}
+
+	if l.Country != "" {
+		return "values-" + language + "-r" + l.Country
+	}
+
+	return "values-" + language
+}
@@ -1,6 +1,7 @@
 package flutter_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/leancodepl/poe2arb/flutter"
@@ -110,3 +111,52 @@ func TestLocaleStringHyphen(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalePluralCategories(t *testing.T) {
+	testCases := []struct {
+		Locale   string
+		Expected []string
+	}{
+		{"en", []string{"one", "other"}},
+		{"en-US", []string{"one", "other"}},
+		{"pl", []string{"one", "few", "many", "other"}},
+		{"ru", []string{"one", "few", "many", "other"}},
+		{"ar", []string{"zero", "one", "two", "few", "many", "other"}},
+		{"lv", []string{"zero", "one", "other"}},
+		{"ja", []string{"other"}},
+		{"zh-Hant-TW", []string{"other"}},
+		{"sr-Cyrl", []string{"one", "few", "other"}},
+		{"xx", []string{"other"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Locale, func(t *testing.T) {
+			locale, err := flutter.ParseLocale(testCase.Locale)
+			assert.NoError(t, err)
+
+			assert.Equal(t, testCase.Expected, locale.PluralCategories())
+		})
+	}
+}
+
+func TestLocaleNativeDirNames(t *testing.T) {
+	testCases := []struct {
+		Input           flutter.Locale
+		ExpectedIOS     string
+		ExpectedAndroid string
+	}{
+		{flutter.Locale{Language: "en"}, "en.lproj", "values-en"},
+		{flutter.Locale{Language: "pt", Country: "BR"}, "pt-BR.lproj", "values-pt-rBR"},
+		{flutter.Locale{Language: "es", Country: "419"}, "es-419.lproj", "values-b+es+419"},
+		{flutter.Locale{Language: "zh", Script: "Hans"}, "zh-Hans.lproj", "values-b+zh+Hans"},
+		{flutter.Locale{Language: "zh", Script: "Hant", Country: "TW"}, "zh-Hant-TW.lproj", "values-b+zh+Hant+TW"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Input.String(), func(t *testing.T) {
+			assert.Equal(t, testCase.ExpectedIOS, testCase.Input.IOSDirName())
+			assert.Equal(t, strings.TrimSuffix(testCase.ExpectedIOS, ".lproj"), testCase.Input.LanguageTag())
+			assert.Equal(t, testCase.ExpectedAndroid, testCase.Input.AndroidDirName())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package flutter
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// PluralCategories are all CLDR plural categories, in the order used in messages.
+var PluralCategories = []string{"zero", "one", "two", "few", "many", "other"}
+
+var pluralCategoryForms = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// PluralCategories returns the CLDR cardinal plural categories used by the locale,
+// e.g. one and other for English or one, few, many and other for Russian.
+// Unknown languages only use the other category.
+func (l Locale) PluralCategories() []string {
+	tag, err := language.Parse(l.StringHyphen())
+	if err != nil {
+		tag = language.Make(l.Language)
+	}
+
+	forms := map[plural.Form]bool{plural.Other: true}
+
+	// x/text doesn't expose the categories of a language, so they are collected
+	// by matching integers and decimals with one fraction digit.
+	for i := 0; i <= 1000; i++ {
+		forms[plural.Cardinal.MatchPlural(tag, i, 0, 0, 0, 0)] = true
+	}
+	for i := 0; i <= 10; i++ {
+		for f := 1; f <= 9; f++ {
+			forms[plural.Cardinal.MatchPlural(tag, i, 1, 1, f, f)] = true
+		}
+	}
+
+	var categories []string
+	for _, category := range PluralCategories {
+		if forms[pluralCategoryForms[category]] {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories
+}
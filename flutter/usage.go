@@ -0,0 +1,278 @@
+package flutter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MessageUsage is an access of a generated message getter in the Dart source.
+type MessageUsage struct {
+	Name string
+	// File is relative to the project root.
+	File string
+	Line int
+}
+
+func (u MessageUsage) String() string {
+	return fmt.Sprintf("%s:%d", u.File, u.Line)
+}
+
+// LocalizationsMembers are members of the class generated by gen-l10n (and of Object).
+// Messages would clash with them and their accesses aren't message usages.
+var LocalizationsMembers = []string{
+	"localeName", "delegate", "localizationsDelegates", "supportedLocales", "of",
+	"hashCode", "runtimeType", "toString", "noSuchMethod",
+}
+
+// DefaultUsagePatterns returns patterns of expressions evaluating to the localizations
+// of the output class, e.g. AppLocalizations.of(context), and of names declared in the Dart
+// source as the localizations, e.g. context.l10n extension getter or a local l10n variable.
+func (c *FlutterConfig) DefaultUsagePatterns() ([]string, error) {
+	outputClass := regexp.QuoteMeta(c.L10n.OutputClass)
+	patterns := []string{`\b` + outputClass + `\.of\(\s*\w+\s*\)`}
+
+	// Getters and variables initialized with the accessor, e.g. get l10n => AppLocalizations.of(this)!
+	// or final l10n = AppLocalizations.of(context), and declarations of the output class type,
+	// e.g. AppLocalizations get l10n or final AppLocalizations l10n.
+	declarationRegexps := []*regexp.Regexp{
+		regexp.MustCompile(`\b([a-zA-Z_]\w*)\s*(?:=>|=)\s*` + outputClass + `\.of\(`),
+		regexp.MustCompile(`\b` + outputClass + `\??\s+(?:get\s+)?([a-zA-Z_]\w*)\b`),
+	}
+
+	var names []string
+	err := c.walkDartSources(func(_, source string) {
+		for _, declarationRegexp := range declarationRegexps {
+			for _, match := range declarationRegexp.FindAllStringSubmatch(source, -1) {
+				if name := match[1]; !slices.Contains(dartKeywords, name) && !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) > 0 {
+		slices.Sort(names)
+		patterns = append(patterns, `\b(?:`+strings.Join(names, "|")+`)\b`)
+	}
+
+	return patterns, nil
+}
+
+// dartKeywords are keywords following the output class name in declarations other than
+// of the localizations, e.g. class MyLocalizations extends AppLocalizations.
+var dartKeywords = []string{"extends", "implements", "with", "on", "get", "set", "operator", "of"}
+
+// FindMessageUsages scans Dart files in the lib directory for message getters accessed
+// on expressions matching the patterns. Comments, string literals except for their
+// interpolations, import, export and part directives and the generated localizations files
+// are skipped. Usages are sorted by their location.
+func (c *FlutterConfig) FindMessageUsages(patterns []string) ([]MessageUsage, error) {
+	var accessPatterns []string
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid usage pattern %q: %w", pattern, err)
+		}
+		accessPatterns = append(accessPatterns, "(?:"+pattern+")")
+	}
+	if len(accessPatterns) == 0 {
+		return nil, nil
+	}
+
+	// Null checks are allowed between the localizations and the getter,
+	// as AppLocalizations.of returns a nullable value.
+	accessRegexp := regexp.MustCompile(`(?:` + strings.Join(accessPatterns, "|") + `)\s*[!?]?\s*\.\s*([a-z][a-zA-Z_\d]*)`)
+
+	var usages []MessageUsage
+	err := c.walkDartSources(func(file, source string) {
+		for _, match := range accessRegexp.FindAllStringSubmatchIndex(source, -1) {
+			if slices.Contains(LocalizationsMembers, source[match[2]:match[3]]) {
+				continue
+			}
+
+			usages = append(usages, MessageUsage{
+				Name: source[match[2]:match[3]],
+				File: file,
+				Line: strings.Count(source[:match[2]], "\n") + 1,
+			})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(usages, func(a, b MessageUsage) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+
+	return usages, nil
+}
+
+// walkDartSources calls fn with the path relative to the project root and the source
+// of every Dart file in the lib directory, except for the generated localizations files.
+// Comments, string literals and directives are blanked out, see stripDartLiterals.
+func (c *FlutterConfig) walkDartSources(fn func(file, source string)) error {
+	generatedPrefix := strings.TrimSuffix(c.L10n.OutputLocalizationFile, filepath.Ext(c.L10n.OutputLocalizationFile))
+
+	libDir := filepath.Join(c.RootDir, "lib")
+	err := filepath.WalkDir(libDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".dart" {
+			return nil
+		}
+		if generatedPrefix != "" && strings.HasPrefix(d.Name(), generatedPrefix) {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(c.RootDir, path)
+		if err != nil {
+			return err
+		}
+
+		fn(filepath.ToSlash(rel), stripDartDirectives(stripDartLiterals(string(contents))))
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scanning Dart files: %w", err)
+	}
+
+	return nil
+}
+
+// stripDartLiterals replaces comments and the text of string literals with spaces, keeping
+// the line breaks so that the lines stay in place. Interpolated expressions, e.g. ${l10n.title},
+// are kept, as they may access messages too.
+func stripDartLiterals(source string) string {
+	s := &dartStripper{source: source, b: []byte(source)}
+	s.code(0, false)
+	return string(s.b)
+}
+
+type dartStripper struct {
+	source string
+	b      []byte
+}
+
+func (s *dartStripper) blank(from, to int) {
+	for i := from; i < to; i++ {
+		if s.b[i] != '\n' {
+			s.b[i] = ' '
+		}
+	}
+}
+
+// code skips code starting at i. If the code is interpolated, it returns the index
+// of the closing bracket. Otherwise, it returns the end of the source.
+func (s *dartStripper) code(i int, interpolated bool) int {
+	source := s.source
+	depth := 0
+
+	for i < len(source) {
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end == -1 {
+				end = len(source) - i
+			}
+			s.blank(i, i+end)
+			i += end
+
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end == -1 {
+				end = len(source) - i
+			} else {
+				end += 4
+			}
+			s.blank(i, i+end)
+			i += end
+
+		case source[i] == '\'' || source[i] == '"':
+			i = s.string(i)
+
+		case source[i] == '{':
+			depth++
+			i++
+
+		case source[i] == '}':
+			if interpolated && depth == 0 {
+				return i
+			}
+			depth--
+			i++
+
+		default:
+			i++
+		}
+	}
+
+	return i
+}
+
+// string blanks the string literal starting at i, except for its interpolated expressions,
+// and returns the index right after it.
+func (s *dartStripper) string(i int) int {
+	source := s.source
+	quote := source[i : i+1]
+	if strings.HasPrefix(source[i:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	raw := i > 0 && source[i-1] == 'r'
+
+	from := i
+	for j := i + len(quote); j < len(source); j++ {
+		switch {
+		case source[j] == '\\' && !raw:
+			j++
+		case strings.HasPrefix(source[j:], "${") && !raw:
+			s.blank(from, j+2)
+			j = s.code(j+2, true)
+			from = j
+		case source[j] == '\n' && len(quote) == 1:
+			// Unterminated single-line string.
+			s.blank(from, j)
+			return j
+		case strings.HasPrefix(source[j:], quote):
+			s.blank(from, j+len(quote))
+			return j + len(quote)
+		}
+	}
+
+	s.blank(from, len(source))
+	return len(source)
+}
+
+// dartDirectiveRegexp matches import, export and part directives, whose URIs
+// often contain paths like package:app/l10n/l10n.dart.
+var dartDirectiveRegexp = regexp.MustCompile(`(?m)^[ \t]*(?:import|export|part)\b[^;]*;`)
+
+// stripDartDirectives replaces directives with spaces, keeping the line breaks
+// so that the lines stay in place.
+func stripDartDirectives(source string) string {
+	return dartDirectiveRegexp.ReplaceAllStringFunc(source, func(directive string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return ' '
+		}, directive)
+	})
+}
@@ -0,0 +1,97 @@
+package flutter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindMessageUsages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/main.dart": `import 'package:flutter/material.dart';
+import 'package:app/l10n/l10n.dart';
+export 'package:app/l10n/l10n.dart'
+    show L10nExtension;
+
+class HomePage extends StatelessWidget {
+  @override
+  Widget build(BuildContext context) {
+    final l10n = AppLocalizations.of(context)!;
+    // l10n.commentedOut
+    /* AppLocalizations.of(context).blockComment */
+    return Column(children: [
+      Text(AppLocalizations.of(context)!.title),
+      Text(l10n.greeting('John')),
+      Text('${context.l10n.itemsCount(3)} // not a comment'),
+      Text('l10n.inString \${l10n.escaped} $l10n.notAnAccess'),
+      Text(context.tr.profile),
+      Text(AppLocalizations.of(context)
+          ?.subtitle ?? ''),
+      Text(l10n.localeName),
+    ]);
+  }
+}
+`,
+		"lib/src/settings.dart": "String label(BuildContext context) => context.l10n.settings;\n",
+		"lib/src/extension.dart": `extension L10nExtension on BuildContext {
+  AppLocalizations get l10n => AppLocalizations.of(this)!;
+  AppLocalizations get tr => AppLocalizations.of(this)!;
+}
+`,
+		"lib/l10n/app_localizations.dart":    "abstract class AppLocalizations { String get title; }\n// l10n.generated\n",
+		"lib/l10n/app_localizations_en.dart": "// l10n.generated\n",
+		"test/widget_test.dart":              "// l10n.notInLib\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o777))
+		assert.NoError(t, os.WriteFile(path, []byte(contents), 0o666))
+	}
+
+	cfg := &flutter.FlutterConfig{
+		RootDir: dir,
+		L10n:    &flutter.L10n{OutputClass: "AppLocalizations", OutputLocalizationFile: "app_localizations.dart"},
+	}
+
+	patterns, err := cfg.DefaultUsagePatterns()
+	assert.NoError(t, err)
+
+	usages, err := cfg.FindMessageUsages(patterns)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []flutter.MessageUsage{
+		{Name: "title", File: "lib/main.dart", Line: 13},
+		{Name: "greeting", File: "lib/main.dart", Line: 14},
+		{Name: "itemsCount", File: "lib/main.dart", Line: 15},
+		{Name: "profile", File: "lib/main.dart", Line: 17},
+		{Name: "subtitle", File: "lib/main.dart", Line: 19},
+		{Name: "settings", File: "lib/src/settings.dart", Line: 1},
+	}, usages)
+
+	t.Run("undeclared names", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "lib", "main.dart")
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o777))
+		assert.NoError(t, os.WriteFile(path, []byte("final l10n = Translations();\nfinal title = l10n.title;\n"), 0o666))
+
+		cfg := &flutter.FlutterConfig{RootDir: dir, L10n: &flutter.L10n{OutputClass: "AppLocalizations"}}
+
+		patterns, err := cfg.DefaultUsagePatterns()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`\bAppLocalizations\.of\(\s*\w+\s*\)`}, patterns)
+
+		usages, err := cfg.FindMessageUsages(patterns)
+		assert.NoError(t, err)
+		assert.Empty(t, usages)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := cfg.FindMessageUsages([]string{"l10n("})
+
+		assert.ErrorContains(t, err, `invalid usage pattern "l10n("`)
+	})
+}
@@ -2,9 +2,11 @@
 package log
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	clr "github.com/TwiN/go-color"
 )
@@ -12,12 +14,20 @@ import (
 type Logger struct {
 	writer io.Writer
 	depth  int
+
+	// mu guards writes to writer, it is shared by all sub-loggers.
+	mu *sync.Mutex
+
+	// parent is set only for buffered loggers, see Buffered.
+	parent *Logger
+	buffer *bytes.Buffer
 }
 
 func New(writer io.Writer) *Logger {
 	return &Logger{
 		writer: writer,
 		depth:  0,
+		mu:     &sync.Mutex{},
 	}
 }
 
@@ -33,6 +43,12 @@ func (l *Logger) Success(msg string, params ...any) *Logger {
 	return l
 }
 
+func (l *Logger) Warning(msg string, params ...any) *Logger {
+	l.log(clr.Yellow, msg, params...)
+
+	return l
+}
+
 func (l *Logger) Error(msg string, params ...any) *Logger {
 	l.log(clr.Red, msg, params...)
 
@@ -50,6 +66,9 @@ func (l *Logger) log(color, msg string, params ...any) {
 	msg = strings.Join(lines, "")
 
 	str := fmt.Sprintf(msg, params...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	fmt.Fprint(l.writer, str)
 }
 
@@ -57,5 +76,44 @@ func (l *Logger) Sub() *Logger {
 	return &Logger{
 		writer: l.writer,
 		depth:  l.depth + 1,
+		mu:     l.mu,
+		parent: l.parent,
+		buffer: l.buffer,
 	}
 }
+
+// Buffered returns a logger at the same depth that keeps its output (and output
+// of its sub-loggers) in memory until Flush is called. Used to keep the output
+// of concurrently running tasks grouped together.
+func (l *Logger) Buffered() *Logger {
+	buffer := &bytes.Buffer{}
+
+	return &Logger{
+		writer: buffer,
+		depth:  l.depth,
+		mu:     &sync.Mutex{},
+		parent: l,
+		buffer: buffer,
+	}
+}
+
+// Flush writes the output buffered so far at once to the logger
+// Buffered was called on. It is a no-op for non-buffered loggers.
+func (l *Logger) Flush() {
+	if l.parent == nil {
+		return
+	}
+
+	l.mu.Lock()
+	out := l.buffer.String()
+	l.buffer.Reset()
+	l.mu.Unlock()
+
+	if out == "" {
+		return
+	}
+
+	l.parent.mu.Lock()
+	defer l.parent.mu.Unlock()
+	fmt.Fprint(l.parent.writer, out)
+}
@@ -9,10 +9,11 @@ import (
 )
 
 const (
-	blue  = "\x1b[34m"
-	green = "\x1b[32m"
-	red   = "\x1b[31m"
-	reset = "\x1b[0m"
+	blue   = "\x1b[34m"
+	green  = "\x1b[32m"
+	red    = "\x1b[31m"
+	yellow = "\x1b[33m"
+	reset  = "\x1b[0m"
 )
 
 func TestLoggerInfo(t *testing.T) {
@@ -35,6 +36,16 @@ func TestLoggerSuccess(t *testing.T) {
 	assert.Equal(t, green+" • "+reset+"Hello, world!\n", buf.String())
 }
 
+func TestLoggerWarning(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := log.New(&buf)
+
+	l.Warning("Hello, %s!", "world")
+
+	assert.Equal(t, yellow+" • "+reset+"Hello, world!\n", buf.String())
+}
+
 func TestLoggerError(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -68,3 +79,23 @@ func TestLoggerMultilineInfo(t *testing.T) {
 
 	assert.Equal(t, blue+" • "+reset+"test one line\n"+blue+" • "+reset+"test second line\n", buf.String())
 }
+
+func TestLoggerBuffered(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := log.New(&buf)
+
+	first := l.Buffered()
+	second := l.Buffered()
+
+	first.Info("first")
+	second.Info("second")
+	first.Sub().Info("first sub")
+
+	assert.Equal(t, "", buf.String())
+
+	second.Flush()
+	first.Flush()
+
+	assert.Equal(t, blue+" • "+reset+"second\n"+blue+" • "+reset+"first\n  "+blue+" • "+reset+"first sub\n", buf.String())
+}
@@ -17,6 +17,9 @@ import (
 
 const apiURL = "https://api.poeditor.com/v2"
 
+// timeLayout is the format of dates returned by the API, e.g. 2015-05-04T14:21:41+0000.
+const timeLayout = "2006-01-02T15:04:05-0700"
+
 type Client struct {
 	apiURL string
 	token  string
@@ -107,9 +110,15 @@ func (c *Client) GetProjectLanguages(projectID string) ([]Language, error) {
 
 	langs := []Language{}
 	for _, lang := range resp.Result.Languages {
+		// Empty for languages with no translations.
+		updated, _ := time.Parse(timeLayout, lang.Updated)
+
 		langs = append(langs, Language{
-			Name: lang.Name,
-			Code: lang.Code,
+			Name:         lang.Name,
+			Code:         lang.Code,
+			Percentage:   lang.Percentage,
+			Translations: lang.Translations,
+			Updated:      updated,
 		})
 	}
 
@@ -132,7 +141,98 @@ func (c *Client) GetExportURL(projectID, languageCode string) (string, error) {
 	return resp.Result.URL, nil
 }
 
-func (c *Client) Upload(projectID, languageCode string, file io.Reader) error {
+// GetTerms returns all terms of the project, without translations.
+func (c *Client) GetTerms(projectID string) ([]Term, error) {
+	var resp termsListResponse
+
+	params := map[string]string{"id": projectID}
+	err := c.request("/terms/list", params, &resp)
+	if err := handleRequestErr(err, resp.baseResponse); err != nil {
+		return nil, err
+	}
+
+	terms := []Term{}
+	for _, term := range resp.Result.Terms {
+		terms = append(terms, Term{
+			Term:    term.Term,
+			Context: term.Context,
+			Tags:    term.Tags,
+		})
+	}
+
+	return terms, nil
+}
+
+// UpdateTermsTags replaces tags of the given terms.
+func (c *Client) UpdateTermsTags(projectID string, terms []Term) error {
+	type termData struct {
+		Term    string   `json:"term"`
+		Context string   `json:"context"`
+		Tags    []string `json:"tags"`
+	}
+
+	var data []termData
+	for _, term := range terms {
+		data = append(data, termData{Term: term.Term, Context: term.Context, Tags: term.Tags})
+	}
+
+	return c.termsRequest("/terms/update", projectID, data)
+}
+
+// UpdateTermsComments replaces comments of the given terms.
+// Uploading terms doesn't change comments of the existing ones.
+func (c *Client) UpdateTermsComments(projectID string, terms []Term) error {
+	type termData struct {
+		Term    string `json:"term"`
+		Context string `json:"context"`
+		Comment string `json:"comment"`
+	}
+
+	var data []termData
+	for _, term := range terms {
+		data = append(data, termData{Term: term.Term, Context: term.Context, Comment: term.Comment})
+	}
+
+	return c.termsRequest("/terms/update", projectID, data)
+}
+
+// DeleteTerms deletes the given terms from the project.
+func (c *Client) DeleteTerms(projectID string, terms []Term) error {
+	type termData struct {
+		Term    string `json:"term"`
+		Context string `json:"context"`
+	}
+
+	var data []termData
+	for _, term := range terms {
+		data = append(data, termData{Term: term.Term, Context: term.Context})
+	}
+
+	return c.termsRequest("/terms/delete", projectID, data)
+}
+
+func (c *Client) termsRequest(path, projectID string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding terms: %w", err)
+	}
+
+	var resp baseResponse
+	params := map[string]string{
+		"id":   projectID,
+		"data": string(encoded),
+	}
+	err = c.request(path, params, &resp)
+	if err := handleRequestErr(err, resp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Upload uploads terms and translations in POEditor JSON format. When overwrite is true,
+// existing translations are overwritten, otherwise only the missing ones are added.
+func (c *Client) Upload(projectID, languageCode string, file io.Reader, overwrite bool) error {
 	reqURL := fmt.Sprintf("%s%s", c.apiURL, "/projects/upload")
 
 	var b bytes.Buffer
@@ -150,7 +250,11 @@ func (c *Client) Upload(projectID, languageCode string, file io.Reader) error {
 	}
 
 	_ = w.WriteField("language", languageCode)
-	_ = w.WriteField("overwrite", "0")
+	if overwrite {
+		_ = w.WriteField("overwrite", "1")
+	} else {
+		_ = w.WriteField("overwrite", "0")
+	}
 
 	err = w.Close()
 	if err != nil {
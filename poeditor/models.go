@@ -1,6 +1,22 @@
 package poeditor
 
+import "time"
+
 type Language struct {
 	Name string
 	Code string
+
+	// Percentage of the project terms translated to the language.
+	Percentage float64
+	// Translations is the number of translated terms.
+	Translations int
+	// Updated is the time of the last translation change, zero if never translated.
+	Updated time.Time
+}
+
+type Term struct {
+	Term    string
+	Context string
+	Comment string
+	Tags    []string
 }
@@ -0,0 +1,67 @@
+package poeditor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Project is a single POEditor project, binding the client to its ID.
+// It's the POEditor implementation of source.Source.
+type Project struct {
+	client *Client
+	id     string
+}
+
+func NewProject(client *Client, projectID string) *Project {
+	return &Project{client: client, id: projectID}
+}
+
+func (p *Project) Languages() ([]Language, error) {
+	return p.client.GetProjectLanguages(p.id)
+}
+
+// Export downloads the JSON export of the language.
+func (p *Project) Export(ctx context.Context, languageCode string) (io.ReadCloser, error) {
+	url, err := p.client.GetExportURL(p.id, languageCode)
+	if err != nil {
+		return nil, fmt.Errorf("getting export URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP request for export: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("making HTTP request for export: %w", err)
+	}
+
+	return resp.Body, nil
+}
+
+func (p *Project) AddLanguage(languageCode string) error {
+	return p.client.AddLanguage(p.id, languageCode)
+}
+
+func (p *Project) Upload(languageCode string, file io.Reader, overwrite bool) error {
+	return p.client.Upload(p.id, languageCode, file, overwrite)
+}
+
+func (p *Project) Terms() ([]Term, error) {
+	return p.client.GetTerms(p.id)
+}
+
+func (p *Project) UpdateTermsTags(terms []Term) error {
+	return p.client.UpdateTermsTags(p.id, terms)
+}
+
+func (p *Project) UpdateTermsComments(terms []Term) error {
+	return p.client.UpdateTermsComments(p.id, terms)
+}
+
+func (p *Project) DeleteTerms(terms []Term) error {
+	return p.client.DeleteTerms(p.id, terms)
+}
@@ -14,8 +14,11 @@ type languagesListResponse struct {
 	baseResponse
 	Result struct {
 		Languages []struct {
-			Name string `json:"name"`
-			Code string `json:"code"`
+			Name         string  `json:"name"`
+			Code         string  `json:"code"`
+			Translations int     `json:"translations"`
+			Percentage   float64 `json:"percentage"`
+			Updated      string  `json:"updated"`
 		} `json:"languages"`
 	} `json:"result"`
 }
@@ -26,3 +29,14 @@ type projectsExportResponse struct {
 		URL string `json:"url"`
 	} `json:"result"`
 }
+
+type termsListResponse struct {
+	baseResponse
+	Result struct {
+		Terms []struct {
+			Term    string   `json:"term"`
+			Context string   `json:"context"`
+			Tags    []string `json:"tags"`
+		} `json:"terms"`
+	} `json:"result"`
+}
@@ -0,0 +1,308 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/convert/arb2poe"
+	"github.com/leancodepl/poe2arb/convert/icu"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/leancodepl/poe2arb/poeditor"
+	"golang.org/x/text/language"
+)
+
+// Dir is a directory of translation files used instead of a translation management system,
+// e.g. a mirror of the project or test fixtures. It contains either POEditor JSON exports
+// named <language code>.json, or ARB files converted to terms with the term prefix.
+// When there are both, ARB files are ignored. Only JSON files can be uploaded to.
+//
+// Languages are updated at the modification times of their files, not when their contents
+// changed, so touching a file reports it as updated and edits keeping the time are missed.
+type Dir struct {
+	dir string
+
+	templateLocale flutter.Locale
+	termPrefix     string
+	syntax         icu.Options
+	readOnly       bool
+	ignored        []string
+}
+
+type DirOptions struct {
+	// TemplateLocale is the locale of the template ARB file, the only one whose
+	// placeholder definitions are kept.
+	TemplateLocale flutter.Locale
+	// TermPrefix is added to the terms converted from ARB files.
+	TermPrefix string
+	// UseEscaping enables ICU quoting with apostrophes in ARB files, the same as gen-l10n use-escaping option.
+	UseEscaping bool
+	// StrictSyntax reports brackets which don't start a placeholder in ARB files as errors.
+	StrictSyntax bool
+	// ReadOnly disallows changing JSON files too.
+	ReadOnly bool
+	// Ignored are names of files in the directory which aren't translation files,
+	// e.g. the snapshot metadata.
+	Ignored []string
+}
+
+func NewDir(dir string, options *DirOptions) *Dir {
+	return &Dir{
+		dir: dir,
+
+		templateLocale: options.TemplateLocale,
+		termPrefix:     options.TermPrefix,
+		syntax:         icu.Options{Escaping: options.UseEscaping, Relaxed: !options.StrictSyntax},
+		readOnly:       options.ReadOnly,
+		ignored:        options.Ignored,
+	}
+}
+
+// dirFile is a translation file of a single language.
+type dirFile struct {
+	path string
+	lang poeditor.Language
+	arb  bool
+}
+
+func (d *Dir) files() ([]dirFile, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonFiles, arbFiles []dirFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || slices.Contains(d.ignored, name) {
+			continue
+		}
+
+		path := filepath.Join(d.dir, name)
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		// The modification time lets the poe command skip unchanged languages.
+		// Contents aren't compared, see the Dir doc.
+		updated := info.ModTime().UTC()
+
+		switch filepath.Ext(name) {
+		case ".json":
+			code := strings.TrimSuffix(name, ".json")
+			if _, err := language.Parse(code); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			jsonFiles = append(jsonFiles, dirFile{
+				path: path,
+				lang: poeditor.Language{Name: code, Code: code, Updated: updated},
+			})
+
+		case ".arb":
+			locale, err := readARBLocale(path)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			code := strings.ToLower(locale.StringHyphen())
+
+			arbFiles = append(arbFiles, dirFile{
+				path: path,
+				lang: poeditor.Language{Name: code, Code: code, Updated: updated},
+				arb:  true,
+			})
+		}
+	}
+
+	if len(jsonFiles) > 0 || len(arbFiles) == 0 {
+		return jsonFiles, nil
+	}
+
+	return arbFiles, nil
+}
+
+func readARBLocale(path string) (flutter.Locale, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return flutter.Locale{}, err
+	}
+
+	var arb struct {
+		Locale string `json:"@@locale"`
+	}
+	if err := json.Unmarshal(data, &arb); err != nil {
+		return flutter.Locale{}, fmt.Errorf("decoding ARB: %w", err)
+	}
+	if arb.Locale == "" {
+		return flutter.Locale{}, errors.New("missing @@locale")
+	}
+
+	return flutter.ParseLocale(arb.Locale)
+}
+
+func (d *Dir) find(languageCode string) (*dirFile, []dirFile, error) {
+	files, err := d.files()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, file := range files {
+		if strings.EqualFold(file.lang.Code, languageCode) {
+			return &file, files, nil
+		}
+	}
+
+	return nil, files, nil
+}
+
+func (d *Dir) Languages() ([]poeditor.Language, error) {
+	files, err := d.files()
+	if err != nil {
+		return nil, err
+	}
+
+	langs := []poeditor.Language{}
+	for _, file := range files {
+		langs = append(langs, file.lang)
+	}
+
+	return langs, nil
+}
+
+// Export returns the JSON file of the language, or its ARB file converted to terms.
+func (d *Dir) Export(_ context.Context, languageCode string) (io.ReadCloser, error) {
+	file, _, err := d.find(languageCode)
+	if err != nil {
+		return nil, err
+	} else if file == nil {
+		return nil, fmt.Errorf("language %s not found in %s", languageCode, d.dir)
+	}
+
+	if !file.arb {
+		return os.Open(file.path)
+	}
+
+	arb, err := os.Open(file.path)
+	if err != nil {
+		return nil, err
+	}
+	defer arb.Close()
+
+	var b bytes.Buffer
+	conv := arb2poe.NewConverter(arb, d.templateLocale, d.termPrefix, d.syntax)
+	if _, err := conv.Convert(&b); errors.Is(err, arb2poe.ErrNoTerms) {
+		b.WriteString("[]")
+	} else if err != nil {
+		return nil, fmt.Errorf("converting %s: %w", filepath.Base(file.path), err)
+	}
+
+	return io.NopCloser(&b), nil
+}
+
+// AddLanguage creates an empty JSON file of the language, if it doesn't exist yet.
+func (d *Dir) AddLanguage(languageCode string) error {
+	file, err := d.writableFile(languageCode)
+	if err != nil {
+		return err
+	} else if file != nil {
+		return nil
+	}
+
+	return writeTerms(filepath.Join(d.dir, languageCode+".json"), []*convert.POETerm{})
+}
+
+// Upload merges the terms into the JSON file of the language, creating it if needed.
+func (d *Dir) Upload(languageCode string, file io.Reader, overwrite bool) error {
+	existing, err := d.writableFile(languageCode)
+	if err != nil {
+		return err
+	}
+
+	var uploaded []*convert.POETerm
+	if err := json.NewDecoder(file).Decode(&uploaded); err != nil {
+		return fmt.Errorf("decoding terms: %w", err)
+	}
+
+	path := filepath.Join(d.dir, languageCode+".json")
+	var terms []*convert.POETerm
+	if existing != nil {
+		path = existing.path
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &terms); err != nil {
+			return fmt.Errorf("decoding %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	return writeTerms(path, mergeTerms(terms, uploaded, overwrite))
+}
+
+// writableFile finds the language file, making sure the directory can be changed
+// and doesn't contain ARB files.
+func (d *Dir) writableFile(languageCode string) (*dirFile, error) {
+	if d.readOnly {
+		return nil, fmt.Errorf("files in %s can't be changed: %w", d.dir, ErrReadOnly)
+	}
+
+	file, files, err := d.find(languageCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) > 0 && files[0].arb {
+		return nil, fmt.Errorf("ARB files in %s can't be changed: %w", d.dir, ErrReadOnly)
+	}
+
+	return file, nil
+}
+
+// mergeTerms adds the uploaded terms to the existing ones. Translations of the existing
+// terms are replaced only when overwriting or when they're empty.
+func mergeTerms(terms, uploaded []*convert.POETerm, overwrite bool) []*convert.POETerm {
+	for _, upload := range uploaded {
+		var existing *convert.POETerm
+		for _, term := range terms {
+			if term.Term == upload.Term && term.Context == upload.Context {
+				existing = term
+				break
+			}
+		}
+
+		if existing == nil {
+			terms = append(terms, upload)
+			continue
+		}
+
+		empty := convert.POETermDefinition{IsPlural: existing.Definition.IsPlural}
+		if overwrite || existing.Definition.Equal(empty) {
+			existing.Definition = upload.Definition
+			existing.TermPlural = upload.TermPlural
+		}
+		if overwrite {
+			existing.Comment = upload.Comment
+		}
+	}
+
+	return terms
+}
+
+func writeTerms(path string, terms []*convert.POETerm) error {
+	data, err := json.MarshalIndent(terms, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encoding terms: %w", err)
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o666)
+}
@@ -0,0 +1,163 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/leancodepl/poe2arb/convert"
+	"github.com/leancodepl/poe2arb/flutter"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, contents := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o666)
+		assert.NoError(t, err)
+	}
+
+	return dir
+}
+
+func newTestDir(dir string) *Dir {
+	return NewDir(dir, &DirOptions{TemplateLocale: flutter.Locale{Language: "en"}})
+}
+
+func languageCodes(t *testing.T, d *Dir) []string {
+	langs, err := d.Languages()
+	assert.NoError(t, err)
+
+	codes := []string{}
+	for _, lang := range langs {
+		codes = append(codes, lang.Code)
+	}
+	return codes
+}
+
+func readExport(t *testing.T, d *Dir, languageCode string) []*convert.POETerm {
+	export, err := d.Export(context.Background(), languageCode)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer export.Close()
+
+	var terms []*convert.POETerm
+	assert.NoError(t, json.NewDecoder(export).Decode(&terms))
+	return terms
+}
+
+func TestDirJSON(t *testing.T) {
+	d := newTestDir(writeTestDir(t, map[string]string{
+		"en.json":    `[{"term": "greeting", "definition": "Hello!"}]`,
+		"pt-br.json": `[{"term": "greeting", "definition": "Olá!"}]`,
+		"app_en.arb": `{"@@locale": "en", "greeting": "Hi!"}`,
+	}))
+
+	assert.Equal(t, []string{"en", "pt-br"}, languageCodes(t, d))
+
+	terms := readExport(t, d, "pt-BR")
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "Olá!", *terms[0].Definition.Value)
+
+	_, err := d.Export(context.Background(), "pl")
+	assert.ErrorContains(t, err, "language pl not found")
+}
+
+func TestDirIgnored(t *testing.T) {
+	dir := writeTestDir(t, map[string]string{
+		"en.json":       `[{"term": "greeting", "definition": "Hello!"}]`,
+		"snapshot.json": `{}`,
+	})
+
+	_, err := newTestDir(dir).Languages()
+	assert.ErrorContains(t, err, "snapshot.json")
+
+	d := NewDir(dir, &DirOptions{Ignored: []string{"snapshot.json"}})
+	assert.Equal(t, []string{"en"}, languageCodes(t, d))
+}
+
+func TestDirARB(t *testing.T) {
+	d := newTestDir(writeTestDir(t, map[string]string{
+		"app_en.arb":    `{"@@locale": "en", "greeting": "Hello, {name}!", "@greeting": {"placeholders": {"name": {}}}}`,
+		"app_pt_BR.arb": `{"@@locale": "pt_BR", "greeting": "Olá, {name}!"}`,
+		"app_pl.arb":    `{"@@locale": "pl"}`,
+	}))
+
+	assert.Equal(t, []string{"en", "pl", "pt-br"}, languageCodes(t, d))
+
+	terms := readExport(t, d, "pt-br")
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "greeting", terms[0].Term)
+	assert.Equal(t, "Olá, {name}!", *terms[0].Definition.Value)
+
+	assert.Empty(t, readExport(t, d, "pl"))
+
+	err := d.Upload("en", strings.NewReader("[]"), true)
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	err = d.AddLanguage("de")
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestDirReadOnly(t *testing.T) {
+	d := NewDir(writeTestDir(t, map[string]string{
+		"en.json": `[{"term": "greeting", "definition": "Hello!"}]`,
+	}), &DirOptions{ReadOnly: true})
+
+	assert.Len(t, readExport(t, d, "en"), 1)
+
+	err := d.Upload("en", strings.NewReader("[]"), true)
+	assert.ErrorIs(t, err, ErrReadOnly)
+
+	err = d.AddLanguage("pl")
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.Equal(t, []string{"en"}, languageCodes(t, d))
+}
+
+func TestDirUpload(t *testing.T) {
+	dir := writeTestDir(t, map[string]string{
+		"en.json": `[
+			{"term": "greeting", "definition": "Hello!"},
+			{"term": "farewell", "definition": ""}
+		]`,
+	})
+	d := newTestDir(dir)
+
+	upload := `[
+		{"term": "greeting", "definition": "Hi!"},
+		{"term": "farewell", "definition": "Bye!"},
+		{"term": "thanks", "definition": "Thanks!"}
+	]`
+
+	err := d.Upload("en", strings.NewReader(upload), false)
+	assert.NoError(t, err)
+
+	definitions := func() map[string]string {
+		result := map[string]string{}
+		for _, term := range readExport(t, d, "en") {
+			result[term.Term] = *term.Definition.Value
+		}
+		return result
+	}
+	assert.Equal(t, map[string]string{"greeting": "Hello!", "farewell": "Bye!", "thanks": "Thanks!"}, definitions())
+
+	err = d.Upload("en", strings.NewReader(upload), true)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"greeting": "Hi!", "farewell": "Bye!", "thanks": "Thanks!"}, definitions())
+
+	err = d.AddLanguage("pl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"en", "pl"}, languageCodes(t, d))
+
+	export, err := d.Export(context.Background(), "pl")
+	assert.NoError(t, err)
+	data, err := io.ReadAll(export)
+	assert.NoError(t, err)
+	assert.NoError(t, export.Close())
+	assert.Equal(t, "[]\n", string(data))
+}
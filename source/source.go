@@ -0,0 +1,47 @@
+// Package source abstracts translation management systems terms are exported from
+// and uploaded to, so that commands don't depend on POEditor directly.
+// Terms are exchanged in POEditor JSON format, see convert.POETerm.
+package source
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/leancodepl/poe2arb/poeditor"
+)
+
+// Source is a translation project.
+type Source interface {
+	// Languages returns the project languages.
+	Languages() ([]poeditor.Language, error)
+	// Export returns the terms of the language along with their translations.
+	Export(ctx context.Context, languageCode string) (io.ReadCloser, error)
+	// AddLanguage adds a language with no translations to the project.
+	AddLanguage(languageCode string) error
+	// Upload adds the terms and their translations to the language. When overwrite is true,
+	// existing translations are overwritten, otherwise only the missing ones are added.
+	// Sources implementing TermsEditor may keep comments of the existing terms.
+	Upload(languageCode string, file io.Reader, overwrite bool) error
+}
+
+// TermsEditor is implemented by sources whose terms can be tagged and deleted.
+type TermsEditor interface {
+	// Terms returns all terms of the project, without translations.
+	Terms() ([]poeditor.Term, error)
+	// UpdateTermsTags replaces tags of the given terms.
+	UpdateTermsTags(terms []poeditor.Term) error
+	// UpdateTermsComments replaces comments of the given terms.
+	UpdateTermsComments(terms []poeditor.Term) error
+	// DeleteTerms deletes the given terms from the project.
+	DeleteTerms(terms []poeditor.Term) error
+}
+
+// ErrReadOnly is returned when changing a source that can only be exported from.
+var ErrReadOnly = errors.New("translation source is read-only")
+
+var (
+	_ Source      = (*poeditor.Project)(nil)
+	_ TermsEditor = (*poeditor.Project)(nil)
+	_ Source      = (*Dir)(nil)
+)